@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/batch"
+	"veatla/simulator/src/scenario"
+)
+
+// runBatch implements the "batch" subcommand: run seeds headless and write a
+// summary per seed.
+func runBatch(args []string) {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	seeds := fs.String("seeds", strconv.Itoa(worldSeed), "comma-separated seeds or a range like 1-100")
+	days := fs.Float64("days", 1, "simulated days per seed")
+	parallel := fs.Int("parallel", runtime.NumCPU(), "seeds simulated concurrently")
+	format := fs.String("format", "csv", "output format: csv or json")
+	out := fs.String("out", "", "output file (default stdout)")
+	agentCount := fs.Int("agents", 0, "initial agents per seed (default: scenario default)")
+	dayLength := fs.Duration("day-length", 0, "simulated length of a day (default: 24m)")
+	archetypesFile := fs.String("archetypes", "", "JSON file with agent archetypes (default: built-in)")
+	fs.Parse(args)
+
+	var archetypes agents.Archetypes
+	if *archetypesFile != "" {
+		loaded, err := agents.LoadArchetypesFile(*archetypesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		archetypes = loaded
+	}
+
+	seedList, err := parseSeeds(*seeds)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	results := batch.Run(batch.Config{
+		Seeds:    seedList,
+		Days:     *days,
+		Step:     tickStep,
+		Parallel: *parallel,
+		Scenario: func(seed int64) scenario.Config {
+			cfg := scenario.Default(seed)
+			if *agentCount > 0 {
+				cfg.Agents = *agentCount
+			}
+			cfg.Archetypes = archetypes
+			cfg.DayLength = *dayLength
+			return cfg
+		},
+	})
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "csv":
+		err = batch.WriteCSV(w, results)
+	case "json":
+		err = batch.WriteJSON(w, results)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parseSeeds(s string) ([]int64, error) {
+	var seeds []int64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if lo, hi, ok := strings.Cut(part, "-"); ok && lo != "" {
+			from, err := strconv.ParseInt(lo, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad seed range %q: %w", part, err)
+			}
+			to, err := strconv.ParseInt(hi, 10, 64)
+			if err != nil || to < from {
+				return nil, fmt.Errorf("bad seed range %q", part)
+			}
+			for seed := from; seed <= to; seed++ {
+				seeds = append(seeds, seed)
+			}
+			continue
+		}
+		seed, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad seed %q: %w", part, err)
+		}
+		seeds = append(seeds, seed)
+	}
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("no seeds given")
+	}
+	return seeds, nil
+}
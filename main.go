@@ -1,42 +1,231 @@
 package main
 
 import (
+	"encoding/json"
+	"log"
+	"os"
 	"time"
 
 	"veatla/simulator/server"
-	"veatla/simulator/src/agents"
+	"veatla/simulator/src/castle"
+	"veatla/simulator/src/clock"
 	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/metrics"
+	"veatla/simulator/src/scenario"
 	"veatla/simulator/src/world"
+	"veatla/simulator/src/zones"
+
+	"github.com/google/uuid"
+)
+
+const (
+	tickStep  = 50 * time.Millisecond
+	frameRate = 50 * time.Millisecond
+	lagReport = 5 * time.Second
+
+	// metricsHistory is the number of ticks kept for /metrics?format=csv|jsonl.
+	metricsHistory = 20 * 60 * 10
 )
 
+const worldSeed = 123456
+
 func main() {
-	ticker := time.NewTicker(50 * time.Millisecond)
-	w := world.NewWorld(123456, 50, 50)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "batch":
+			runBatch(os.Args[2:])
+			return
+		}
+	}
+	runServer()
+}
+
+func runServer() {
+	frame := time.NewTicker(frameRate)
+	clk := clock.New(tickStep)
+	w := scenario.Build(scenario.Default(worldSeed))
 
+	rec := metrics.NewRecorder(metricsHistory)
+	metrics.RegisterSimulation(rec)
+	metrics.SubscribeEvents(rec, w.Events)
+	server.SubscribeEvents(w.Events)
+	logEvents(w.Events)
+	server.Handle("/metrics", rec)
+	server.Handle("/api/prices", w.PriceHistory)
+	server.Handle("/api/ledger", w.Ledger)
+	server.Handle("/api/fiscal", w.Castle.Archive)
+	server.Handle("/api/households", w.HouseholdDirectory)
 	go server.StartWebSocketServer()
 
-	w.Obstacles = append(w.Obstacles,
-		constructions.CreateObstacle(1, 1, 10, 10),
-		constructions.CreateObstacle(15, 15, 25, 25),
-		constructions.CreateObstacle(30, 5, 40, 15),
-		constructions.CreateObstacle(40, 40, 50, 50),
-	)
+	tick := 0
+	var lastLagReport time.Time
+	for {
+		for _, cmd := range server.DrainCommands() {
+			handleCommand(clk, w, cmd)
+		}
 
-	for i := range w.Obstacles {
-		obstacle := &w.Obstacles[i]
-		w.Grid.Insert(obstacle.ID, obstacle.MinX, obstacle.MinZ, obstacle.MaxX, obstacle.MaxZ, true)
-	}
+		batch := time.Now()
+		n := clk.Advance(batch)
+		for range n {
+			tick++
+			start := time.Now()
+			updated := w.Tick(clk.Step())
+			metrics.RecordTick(rec, time.Since(start), w.LastTickStats())
+
+			server.BroadcastWorld(server.Frame{
+				Tick:      tick,
+				Time:      w.Now(),
+				Weather:   w.Weather,
+				Updated:   updated,
+				Obstacles: w.Obstacles,
+				Buildings: w.Buildings,
+				Gates:     w.Gates(),
+				Sites:     w.Sites,
+				Zones:     w.Zones,
+				Items:     w.Items,
+				Nodes:     w.Nodes,
+			})
+			clients, sent := server.Stats()
+			rec.Set(metrics.Clients, float64(clients))
+			rec.Set(metrics.BroadcastBytes, float64(sent))
+			rec.Flush(tick)
+		}
+		clk.Ran(time.Since(batch))
+
+		if lag := clk.Lag(); lag > 0 && time.Since(lastLagReport) > lagReport {
+			log.Printf("simulation is behind real time: dropped %v this frame, %v total", lag, clk.Dropped())
+			lastLagReport = time.Now()
+		}
 
-	for range 1 {
-		w.Agents = append(w.Agents, agents.CreateSimpleAgent(&w))
+		if !clk.MaxSpeed() {
+			<-frame.C
+		}
 	}
+}
 
-	tickDur := 50 * time.Millisecond
-	tick := 0
-	for range ticker.C {
-		tick++
-		w.Grid.Clear(false)
-		updated := w.AgentsTick(tickDur)
-		server.BroadcastWorld(tick, updated, w.Obstacles)
+// logEvents prints events that usually point at a map or pathfinding problem.
+func logEvents(bus *events.Bus) {
+	bus.Subscribe(func(r events.Record) {
+		switch e := r.Event.(type) {
+		case events.AgentStuck:
+			log.Printf("Agent %s is STUCK at position (%.2f, %.2f) for %d ticks",
+				e.AgentID.String()[:8], e.X, e.Z, e.Ticks)
+		case events.PathFailed:
+			log.Printf("Agent %s found no path from (%.2f, %.2f) to (%.2f, %.2f)",
+				e.AgentID.String()[:8], e.FromX, e.FromZ, e.TargetX, e.TargetZ)
+		}
+	})
+}
+
+func handleCommand(clk *clock.Clock, w *world.World, cmd server.Command) {
+	switch cmd.Type {
+	case "clock":
+		var ctrl clock.Control
+		if err := json.Unmarshal(cmd.Data, &ctrl); err != nil {
+			log.Println("clock command decode error:", err)
+			return
+		}
+		if err := clk.Apply(ctrl); err != nil {
+			log.Println("clock command error:", err)
+		}
+	case "policy":
+		var u castle.PolicyUpdate
+		if err := json.Unmarshal(cmd.Data, &u); err != nil {
+			log.Println("policy command decode error:", err)
+			return
+		}
+		if err := w.SetPolicy(u); err != nil {
+			log.Println("policy command error:", err)
+		}
+	case "placeSite":
+		var req struct {
+			Kind     constructions.BuildingKind `json:"kind"`
+			X        float64                    `json:"x"`
+			Z        float64                    `json:"z"`
+			Priority int                        `json:"priority"`
+		}
+		if err := json.Unmarshal(cmd.Data, &req); err != nil {
+			log.Println("placeSite command decode error:", err)
+			return
+		}
+		if _, err := w.PlaceSite(req.Kind, req.X, req.Z, req.Priority); err != nil {
+			log.Println("placeSite command error:", err)
+		}
+	case "cancelSite", "prioritiseSite":
+		var req struct {
+			ID       uuid.UUID `json:"id"`
+			Priority int       `json:"priority"`
+		}
+		if err := json.Unmarshal(cmd.Data, &req); err != nil {
+			log.Println(cmd.Type, "command decode error:", err)
+			return
+		}
+		var err error
+		if cmd.Type == "cancelSite" {
+			err = w.CancelSite(req.ID)
+		} else {
+			err = w.PrioritiseSite(req.ID, req.Priority)
+		}
+		if err != nil {
+			log.Println(cmd.Type, "command error:", err)
+		}
+	case "gate":
+		var req struct {
+			ID   uuid.UUID `json:"id"`
+			Open bool      `json:"open"`
+		}
+		if err := json.Unmarshal(cmd.Data, &req); err != nil {
+			log.Println("gate command decode error:", err)
+			return
+		}
+		if err := w.SetGate(req.ID, req.Open); err != nil {
+			log.Println("gate command error:", err)
+		}
+	case "designateZone":
+		var req struct {
+			Kind     zones.Kind    `json:"kind"`
+			Points   []zones.Point `json:"points"`
+			Factions []string      `json:"factions"`
+			Crop     string        `json:"crop"`
+		}
+		if err := json.Unmarshal(cmd.Data, &req); err != nil {
+			log.Println("designateZone command decode error:", err)
+			return
+		}
+		z, err := zones.NewPolygon(w.NewID(), req.Kind, req.Points)
+		if err != nil {
+			log.Println("designateZone command error:", err)
+			return
+		}
+		z.Factions = req.Factions
+		z.Crop = req.Crop
+		if _, err := w.DesignateZone(z); err != nil {
+			log.Println("designateZone command error:", err)
+		}
+	case "removeZone", "tillField", "sowField", "harvestField":
+		var req struct {
+			ID uuid.UUID `json:"id"`
+		}
+		if err := json.Unmarshal(cmd.Data, &req); err != nil {
+			log.Println(cmd.Type, "command decode error:", err)
+			return
+		}
+		var err error
+		switch cmd.Type {
+		case "removeZone":
+			err = w.RemoveZone(req.ID)
+		case "tillField":
+			err = w.TillField(req.ID)
+		case "sowField":
+			err = w.SowField(req.ID)
+		default:
+			err = w.HarvestField(req.ID)
+		}
+		if err != nil {
+			log.Println(cmd.Type, "command error:", err)
+		}
+	default:
+		log.Println("unknown command:", cmd.Type)
 	}
 }
@@ -4,15 +4,37 @@ import (
 	"math"
 
 	"veatla/simulator/src/agents"
+	"veatla/simulator/src/calendar"
 	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/goods"
+	"veatla/simulator/src/resources"
+	"veatla/simulator/src/weather"
+	"veatla/simulator/src/zones"
 )
 
 const broadcastBatchSize = 1000
 
-// BroadcastWorld sends updated agents and obstacles to connected WebSocket clients in batches.
-func BroadcastWorld(tick int, updated []agents.Agent, obstacles []constructions.Obstacle) {
-	obsSnap := make([]ObstacleSnapshot, 0, len(obstacles))
-	for _, o := range obstacles {
+// Frame is the world state sent to clients after a tick.
+type Frame struct {
+	Tick int
+	// Time is the in-world time, for lighting and the clock display.
+	Time      calendar.Time
+	Weather   weather.State
+	Updated   []agents.Agent
+	Obstacles []constructions.Obstacle
+	Buildings []constructions.Building
+	Gates     []constructions.Fortification
+	Sites     []constructions.Site
+	Zones     []zones.Zone
+	Items     []goods.Item
+	Nodes     []resources.Node
+}
+
+// BroadcastWorld sends a frame to connected WebSocket clients, batching updated agents.
+func BroadcastWorld(f Frame) {
+	tick, updated := f.Tick, f.Updated
+	obsSnap := make([]ObstacleSnapshot, 0, len(f.Obstacles)+len(f.Buildings)+len(f.Gates)+len(f.Nodes))
+	for _, o := range f.Obstacles {
 		obsSnap = append(obsSnap, ObstacleSnapshot{
 			ID:   o.ID,
 			MinX: o.MinX,
@@ -22,10 +44,90 @@ func BroadcastWorld(tick int, updated []agents.Agent, obstacles []constructions.
 			Type: "obstacle",
 		})
 	}
+	for _, b := range f.Buildings {
+		obsSnap = append(obsSnap, ObstacleSnapshot{
+			ID:   b.ID,
+			MinX: b.MinX,
+			MinZ: b.MinZ,
+			MaxX: b.MaxX,
+			MaxZ: b.MaxZ,
+			Type: string(b.Kind),
+		})
+	}
+	for _, g := range f.Gates {
+		obsSnap = append(obsSnap, ObstacleSnapshot{
+			ID:     g.ID,
+			MinX:   g.MinX,
+			MinZ:   g.MinZ,
+			MaxX:   g.MaxX,
+			MaxZ:   g.MaxZ,
+			Type:   string(g.Kind),
+			Closed: !g.Open,
+		})
+	}
+	for _, n := range f.Nodes {
+		if n.Depleted() {
+			continue
+		}
+		obsSnap = append(obsSnap, ObstacleSnapshot{
+			ID:     n.ID,
+			MinX:   n.MinX,
+			MinZ:   n.MinZ,
+			MaxX:   n.MaxX,
+			MaxZ:   n.MaxZ,
+			Type:   string(n.Kind),
+			Amount: n.Amount,
+		})
+	}
+
+	var itemSnap []ItemSnapshot
+	for _, it := range f.Items {
+		itemSnap = append(itemSnap, ItemSnapshot{ID: it.ID, X: it.X, Z: it.Z, Good: string(it.Good), Qty: it.Qty})
+	}
+
+	var siteSnap []SiteSnapshot
+	for i := range f.Sites {
+		s := &f.Sites[i]
+		ss := SiteSnapshot{
+			ID:         s.ID,
+			MinX:       s.MinX,
+			MinZ:       s.MinZ,
+			MaxX:       s.MaxX,
+			MaxZ:       s.MaxZ,
+			Type:       string(s.Blueprint.Kind),
+			Progress:   s.Progress,
+			Priority:   s.Priority,
+			Scaffolded: s.Scaffolded,
+		}
+		for _, g := range s.Blueprint.Materials.Goods() {
+			if n := s.Needs(g); n > 0 {
+				if ss.Missing == nil {
+					ss.Missing = map[string]int{}
+				}
+				ss.Missing[string(g)] = n
+			}
+		}
+		siteSnap = append(siteSnap, ss)
+	}
+
+	var zoneSnap []ZoneSnapshot
+	for i := range f.Zones {
+		z := &f.Zones[i]
+		zs := ZoneSnapshot{ID: z.ID, Type: string(z.Kind), Points: z.Points, Stage: string(z.Stage), Crop: z.Crop, Growth: z.Growth}
+		for _, g := range z.Stock.Goods() {
+			if zs.Stock == nil {
+				zs.Stock = map[string]int{}
+			}
+			zs.Stock[string(g)] = z.Stock[g]
+		}
+		zoneSnap = append(zoneSnap, zs)
+	}
+
+	evSnap, removed := takePending()
 
 	total := len(updated)
 	if total == 0 {
-		msg := BroadcastMessage{Tick: tick, Updated: []AgentSnapshot{}, Obstacles: obsSnap}
+		msg := BroadcastMessage{Tick: tick, Time: f.Time, Weather: f.Weather, Updated: []AgentSnapshot{}, Obstacles: obsSnap, Sites: siteSnap, Zones: zoneSnap, Items: itemSnap, Events: evSnap, Removed: removed}
 		hub.broadcast(msg)
 		return
 	}
@@ -42,9 +144,16 @@ func BroadcastWorld(tick int, updated []agents.Agent, obstacles []constructions.
 				ID:       a.ID,
 				X:        a.X,
 				Z:        a.Z,
-				Type:     "agent",
+				Type:     a.Archetype,
 				Rotation: math.Atan2(a.VZ, a.VX) + math.Pi/2,
 				NoPath:   a.NoPath,
+				Load:     a.Inventory.Load(),
+			}
+			if !a.Inventory.Empty() {
+				as.Carrying = make(map[string]int, len(a.Inventory.Items))
+				for g, n := range a.Inventory.Items {
+					as.Carrying[string(g)] = n
+				}
 			}
 			if len(a.GetPath()) > 0 {
 				as.Path = make([]struct {
@@ -58,7 +167,8 @@ func BroadcastWorld(tick int, updated []agents.Agent, obstacles []constructions.
 			}
 			snap = append(snap, as)
 		}
-		msg := BroadcastMessage{Tick: tick, Updated: snap, Obstacles: obsSnap}
+		msg := BroadcastMessage{Tick: tick, Time: f.Time, Weather: f.Weather, Updated: snap, Obstacles: obsSnap, Sites: siteSnap, Zones: zoneSnap, Items: itemSnap, Events: evSnap, Removed: removed}
 		hub.broadcast(msg)
+		evSnap, removed = nil, nil
 	}
 }
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+)
+
+// Command is a control message received from a WebSocket client.
+type Command struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+const commandQueueSize = 256
+
+var commands = make(chan Command, commandQueueSize)
+
+func enqueueCommand(raw []byte) {
+	var cmd Command
+	if err := json.Unmarshal(raw, &cmd); err != nil {
+		log.Println("ws command decode error:", err)
+		return
+	}
+	select {
+	case commands <- cmd:
+	default:
+		log.Println("ws command queue full, dropping", cmd.Type)
+	}
+}
+
+// DrainCommands returns all commands received since the last call without
+// blocking. It is meant to be called from the simulation loop so commands are
+// applied between ticks.
+func DrainCommands() []Command {
+	var out []Command
+	for {
+		select {
+		case cmd := <-commands:
+			out = append(out, cmd)
+		default:
+			return out
+		}
+	}
+}
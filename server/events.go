@@ -0,0 +1,47 @@
+package server
+
+import (
+	"sync"
+
+	"veatla/simulator/src/events"
+
+	"github.com/google/uuid"
+)
+
+// maxPendingEvents caps how many events are held between broadcasts.
+const maxPendingEvents = 1000
+
+var pending = struct {
+	mu      sync.Mutex
+	events  []EventSnapshot
+	removed []uuid.UUID
+}{}
+
+// SubscribeEvents forwards bus events to clients with the next broadcast.
+// Despawned agents are also listed in the message's removed field so clients
+// can drop them without parsing events.
+func SubscribeEvents(bus *events.Bus) (unsubscribe func()) {
+	return bus.Subscribe(func(r events.Record) {
+		pending.mu.Lock()
+		defer pending.mu.Unlock()
+
+		if e, ok := r.Event.(events.AgentDespawned); ok {
+			pending.removed = append(pending.removed, e.AgentID)
+		}
+		if len(pending.events) < maxPendingEvents {
+			pending.events = append(pending.events, EventSnapshot{
+				Tick: r.Tick,
+				Kind: string(r.Event.Kind()),
+				Data: r.Event,
+			})
+		}
+	})
+}
+
+func takePending() ([]EventSnapshot, []uuid.UUID) {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	evs, removed := pending.events, pending.removed
+	pending.events, pending.removed = nil, nil
+	return evs, removed
+}
@@ -4,13 +4,15 @@ import (
 	"encoding/json"
 	"log"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gorilla/websocket"
 )
 
 type wsHub struct {
-	mu    sync.Mutex
-	conns map[*websocket.Conn]bool
+	mu        sync.Mutex
+	conns     map[*websocket.Conn]bool
+	bytesSent atomic.Uint64
 }
 
 var hub = &wsHub{conns: make(map[*websocket.Conn]bool)}
@@ -42,6 +44,19 @@ func (h *wsHub) broadcast(v interface{}) {
 			log.Println("ws write error, removing conn:", err)
 			_ = c.Close()
 			delete(h.conns, c)
+			continue
 		}
+		h.bytesSent.Add(uint64(len(b)))
 	}
 }
+
+func (h *wsHub) clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.conns)
+}
+
+// Stats returns the number of connected clients and the total bytes broadcast.
+func Stats() (clients int, bytesSent uint64) {
+	return hub.clients(), hub.bytesSent.Load()
+}
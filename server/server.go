@@ -11,6 +11,11 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
+// Handle registers an additional HTTP handler served next to /ws.
+func Handle(pattern string, h http.Handler) {
+	http.Handle(pattern, h)
+}
+
 // StartWebSocketServer starts an HTTP server with a /ws endpoint.
 func StartWebSocketServer() {
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
@@ -21,10 +26,12 @@ func StartWebSocketServer() {
 		}
 		hub.addConn(c)
 		for {
-			if _, _, err := c.ReadMessage(); err != nil {
+			_, msg, err := c.ReadMessage()
+			if err != nil {
 				hub.removeConn(c)
 				return
 			}
+			enqueueCommand(msg)
 		}
 	})
 
@@ -1,6 +1,12 @@
 package server
 
-import "github.com/google/uuid"
+import (
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/weather"
+	"veatla/simulator/src/zones"
+
+	"github.com/google/uuid"
+)
 
 // AgentSnapshot is the JSON shape for one agent sent to clients.
 type AgentSnapshot struct {
@@ -10,7 +16,11 @@ type AgentSnapshot struct {
 	Rotation float64   `json:"rotation"`
 	Type     string    `json:"type"`
 	NoPath   bool      `json:"noPath,omitempty"`
-	Path     []struct {
+	// Carrying is units per good in the agent's inventory.
+	Carrying map[string]int `json:"carrying,omitempty"`
+	// Load is carried weight as a fraction of capacity.
+	Load float64 `json:"load,omitempty"`
+	Path []struct {
 		X float64 `json:"x"`
 		Z float64 `json:"z"`
 	} `json:"path,omitempty"`
@@ -24,11 +34,65 @@ type ObstacleSnapshot struct {
 	MaxX float64   `json:"maxX"`
 	MaxZ float64   `json:"maxZ"`
 	Type string    `json:"type"`
+	// Closed is set for gates that are shut.
+	Closed bool `json:"closed,omitempty"`
+	// Amount is what is left in a resource node.
+	Amount int `json:"amount,omitempty"`
+}
+
+// SiteSnapshot is the JSON shape for one construction site.
+type SiteSnapshot struct {
+	ID         uuid.UUID      `json:"id"`
+	MinX       float64        `json:"minX"`
+	MinZ       float64        `json:"minZ"`
+	MaxX       float64        `json:"maxX"`
+	MaxZ       float64        `json:"maxZ"`
+	Type       string         `json:"type"`
+	Progress   float64        `json:"progress"`
+	Priority   int            `json:"priority"`
+	Scaffolded bool           `json:"scaffolded,omitempty"`
+	Missing    map[string]int `json:"missing,omitempty"`
+}
+
+// ZoneSnapshot is the JSON shape for one designated zone.
+type ZoneSnapshot struct {
+	ID     uuid.UUID     `json:"id"`
+	Type   string        `json:"type"`
+	Points []zones.Point `json:"points"`
+	// Stage, Crop and Growth are set for farm fields.
+	Stage  string  `json:"stage,omitempty"`
+	Crop   string  `json:"crop,omitempty"`
+	Growth float64 `json:"growth,omitempty"`
+	// Stock is set for storage zones holding goods.
+	Stock map[string]int `json:"stock,omitempty"`
+}
+
+// ItemSnapshot is the JSON shape for goods lying on the ground.
+type ItemSnapshot struct {
+	ID   uuid.UUID `json:"id"`
+	X    float64   `json:"x"`
+	Z    float64   `json:"z"`
+	Good string    `json:"good"`
+	Qty  int       `json:"qty"`
+}
+
+// EventSnapshot is the JSON shape for one simulation event sent to clients.
+type EventSnapshot struct {
+	Tick int    `json:"tick"`
+	Kind string `json:"kind"`
+	Data any    `json:"data"`
 }
 
 // BroadcastMessage is the message sent to WebSocket clients each tick.
 type BroadcastMessage struct {
 	Tick      int                `json:"tick"`
+	Time      calendar.Time      `json:"time"`
+	Weather   weather.State      `json:"weather"`
 	Updated   []AgentSnapshot    `json:"updated"`
 	Obstacles []ObstacleSnapshot `json:"obstacles"`
+	Sites     []SiteSnapshot     `json:"sites,omitempty"`
+	Zones     []ZoneSnapshot     `json:"zones,omitempty"`
+	Items     []ItemSnapshot     `json:"items,omitempty"`
+	Events    []EventSnapshot    `json:"events,omitempty"`
+	Removed   []uuid.UUID        `json:"removed,omitempty"`
 }
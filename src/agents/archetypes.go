@@ -0,0 +1,173 @@
+package agents
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Behaviour is what an agent does when nothing more specific (a job or a
+// schedule) tells it what to do.
+type Behaviour string
+
+const (
+	BehaviourWander Behaviour = "wander"
+	BehaviourFarm   Behaviour = "farm"
+	BehaviourGather Behaviour = "gather"
+	BehaviourHaul   Behaviour = "haul"
+	BehaviourBuild  Behaviour = "build"
+	BehaviourPatrol Behaviour = "patrol"
+	BehaviourTrade  Behaviour = "trade"
+	BehaviourIdle   Behaviour = "idle"
+)
+
+// NeedRates is how much each need grows per simulated day.
+type NeedRates struct {
+	Hunger float64 `json:"hunger"`
+	Rest   float64 `json:"rest"`
+}
+
+// Archetype is a data-driven agent definition.
+type Archetype struct {
+	Name          string             `json:"name"`
+	Width         float64            `json:"width"`
+	Height        float64            `json:"height"`
+	MinSpeed      float64            `json:"minSpeed"`
+	MaxSpeed      float64            `json:"maxSpeed"`
+	CarryCapacity float64            `json:"carryCapacity"`
+	StartingMoney int64              `json:"startingMoney"`
+	Skills        map[string]float64 `json:"skills"`
+	Needs         NeedRates          `json:"needs"`
+	Behaviour     Behaviour          `json:"behaviour"`
+	// Faction lets the agent through closed gates that admit it; empty for
+	// commoners.
+	Faction        string  `json:"faction,omitempty"`
+	WanderRadius   float64 `json:"wanderRadius"`
+	StuckThreshold int     `json:"stuckThreshold"`
+	ChangeDirMin   int     `json:"changeDirMin"`
+	ChangeDirMax   int     `json:"changeDirMax"`
+	// SpawnWeight is the relative chance that an immigrant has this archetype.
+	SpawnWeight float64 `json:"spawnWeight"`
+	// Routine is the daily schedule; empty means DefaultRoutine.
+	Routine []Slot `json:"routine,omitempty"`
+	// ViewRadius is how far the agent sees and FieldOfView how wide, in
+	// degrees; zero means the defaults.
+	ViewRadius  float64 `json:"viewRadius,omitempty"`
+	FieldOfView float64 `json:"fieldOfView,omitempty"`
+}
+
+// Archetypes maps archetype names to definitions.
+type Archetypes map[string]Archetype
+
+// DefaultArchetype is used when no archetype is requested.
+const DefaultArchetype = "peasant"
+
+//go:embed archetypes.json
+var defaultArchetypesJSON []byte
+
+// DefaultArchetypes returns the built-in archetype definitions.
+func DefaultArchetypes() Archetypes {
+	defs, err := parseArchetypes(defaultArchetypesJSON)
+	if err != nil {
+		panic(fmt.Sprintf("built-in archetypes: %v", err))
+	}
+	return defs
+}
+
+// LoadArchetypes reads archetype definitions from a JSON array.
+func LoadArchetypes(r io.Reader) (Archetypes, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseArchetypes(data)
+}
+
+// LoadArchetypesFile reads archetype definitions from a JSON file.
+func LoadArchetypesFile(path string) (Archetypes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadArchetypes(f)
+}
+
+func parseArchetypes(data []byte) (Archetypes, error) {
+	var list []Archetype
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	defs := make(Archetypes, len(list))
+	for _, a := range list {
+		if err := a.validate(); err != nil {
+			return nil, err
+		}
+		if _, dup := defs[a.Name]; dup {
+			return nil, fmt.Errorf("archetype %q defined twice", a.Name)
+		}
+		defs[a.Name] = a
+	}
+	return defs, nil
+}
+
+func (a Archetype) validate() error {
+	switch {
+	case a.Name == "":
+		return fmt.Errorf("archetype without a name")
+	case a.Width <= 0 || a.Height <= 0:
+		return fmt.Errorf("archetype %q: size must be positive", a.Name)
+	case a.MinSpeed < 0 || a.MaxSpeed < a.MinSpeed:
+		return fmt.Errorf("archetype %q: invalid speed range", a.Name)
+	case a.StartingMoney < 0:
+		return fmt.Errorf("archetype %q: startingMoney must not be negative", a.Name)
+	case a.WanderRadius <= 0:
+		return fmt.Errorf("archetype %q: wanderRadius must be positive", a.Name)
+	case a.StuckThreshold <= 0:
+		return fmt.Errorf("archetype %q: stuckThreshold must be positive", a.Name)
+	case a.ChangeDirMin < 0 || a.ChangeDirMax < a.ChangeDirMin:
+		return fmt.Errorf("archetype %q: invalid changeDir range", a.Name)
+	case a.ViewRadius < 0:
+		return fmt.Errorf("archetype %q: viewRadius must not be negative", a.Name)
+	case a.FieldOfView < 0 || a.FieldOfView > 360:
+		return fmt.Errorf("archetype %q: fieldOfView must be within 0-360 degrees", a.Name)
+	}
+	if err := validateRoutine(a.Routine); err != nil {
+		return fmt.Errorf("archetype %q: %w", a.Name, err)
+	}
+	return nil
+}
+
+// Get returns the named archetype, falling back to DefaultArchetype.
+func (defs Archetypes) Get(name string) (Archetype, bool) {
+	if a, ok := defs[name]; ok {
+		return a, true
+	}
+	a, ok := defs[DefaultArchetype]
+	return a, ok
+}
+
+// Pick chooses an archetype by SpawnWeight given a uniform roll in [0, 1).
+// Names are visited in sorted order so the result is deterministic.
+func (defs Archetypes) Pick(roll float64) Archetype {
+	names := make([]string, 0, len(defs))
+	total := 0.0
+	for name, a := range defs {
+		names = append(names, name)
+		total += a.SpawnWeight
+	}
+	sort.Strings(names)
+
+	target := roll * total
+	for _, name := range names {
+		target -= defs[name].SpawnWeight
+		if target < 0 {
+			return defs[name]
+		}
+	}
+	a, _ := defs.Get(DefaultArchetype)
+	return a
+}
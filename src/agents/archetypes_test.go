@@ -0,0 +1,111 @@
+package agents
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/events"
+	worldQuery "veatla/simulator/src/world-query"
+
+	"github.com/google/uuid"
+)
+
+// field is an open, clear-weather world with nothing in it but walls.
+type field struct {
+	rng  *rand.Rand
+	seed int64
+	size float64
+	// wall reports whether (x, z) is walled off; nil means nowhere is.
+	wall func(x, z float64) bool
+}
+
+func newField(seed int64) *field {
+	return &field{rng: rand.New(rand.NewSource(seed)), seed: seed, size: 100}
+}
+
+func (f *field) IsPointBlocked(x, z float64, _ worldQuery.Passer) bool {
+	return x < 0 || z < 0 || x >= f.size || z >= f.size || f.wall != nil && f.wall(x, z)
+}
+func (f *field) RandomFloat() float64 { return f.rng.Float64() }
+func (f *field) NewID() uuid.UUID {
+	id, _ := uuid.NewRandomFromReader(f.rng)
+	return id
+}
+func (f *field) GetWorldSeed() int64               { return f.seed }
+func (f *field) GetBoundaries() (float64, float64) { return f.size, f.size }
+func (f *field) DayLength() time.Duration          { return calendar.DefaultDayLength }
+func (f *field) Now() calendar.Time                { return calendar.Default().At(0) }
+func (f *field) MoveCost(x, z float64) float64     { return 1 }
+func (f *field) Emit(e events.Event)               {}
+
+func TestDefaultArchetypes(t *testing.T) {
+	defs := DefaultArchetypes()
+	for _, name := range []string{"peasant", "hauler", "builder", "soldier", "merchant", "noble"} {
+		if _, ok := defs[name]; !ok {
+			t.Errorf("no %s archetype", name)
+		}
+	}
+	if defs["soldier"].Faction == "" {
+		t.Error("soldiers belong to no faction")
+	}
+}
+
+func TestLoadArchetypesRejectsBadDefinitions(t *testing.T) {
+	valid := `"width": 1, "height": 1, "minSpeed": 0.01, "maxSpeed": 0.02, "wanderRadius": 5, "stuckThreshold": 10`
+	for _, tc := range []struct {
+		name, json, err string
+	}{
+		{"no name", `[{` + valid + `}]`, "without a name"},
+		{"twice", `[{"name": "a", ` + valid + `}, {"name": "a", ` + valid + `}]`, "defined twice"},
+		{"speed", `[{"name": "a", ` + valid + `, "minSpeed": 0.5}]`, "speed range"},
+		{"fov", `[{"name": "a", ` + valid + `, "fieldOfView": 400}]`, "fieldOfView"},
+		{"routine", `[{"name": "a", ` + valid + `, "routine": [{"from": 1, "to": 2, "activity": "nap"}]}]`, "unknown activity"},
+	} {
+		_, err := LoadArchetypes(strings.NewReader(tc.json))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: err = %v, want one mentioning %q", tc.name, err, tc.err)
+		}
+	}
+	defs, err := LoadArchetypes(strings.NewReader(`[{"name": "a", ` + valid + `}]`))
+	if err != nil || len(defs) != 1 {
+		t.Fatalf("valid definition: %v, %v", defs, err)
+	}
+}
+
+func TestGetAndPick(t *testing.T) {
+	defs := Archetypes{
+		DefaultArchetype: {Name: DefaultArchetype, SpawnWeight: 1},
+		"guard":          {Name: "guard", SpawnWeight: 3},
+	}
+	if a, ok := defs.Get("dragon"); !ok || a.Name != DefaultArchetype {
+		t.Errorf("unknown name gave %q, want the default", a.Name)
+	}
+	// Names are weighed in sorted order: guard takes [0, 0.75).
+	for roll, want := range map[float64]string{0: "guard", 0.7: "guard", 0.8: DefaultArchetype, 0.99: DefaultArchetype} {
+		if got := defs.Pick(roll).Name; got != want {
+			t.Errorf("Pick(%v) = %s, want %s", roll, got, want)
+		}
+	}
+}
+
+func TestCreateFromArchetype(t *testing.T) {
+	def, _ := DefaultArchetypes().Get("hauler")
+	a := CreateFromArchetypeAt(newField(1), def, 10, 20)
+	if a.Archetype != "hauler" || a.Behaviour != BehaviourHaul || a.Inventory.Capacity != def.CarryCapacity ||
+		a.Money != def.StartingMoney || a.X != 10 || a.Z != 20 {
+		t.Fatalf("agent does not match its archetype: %+v", a)
+	}
+	if a.baseSpeed < def.MinSpeed || a.baseSpeed > def.MaxSpeed {
+		t.Errorf("speed %v outside %v-%v", a.baseSpeed, def.MinSpeed, def.MaxSpeed)
+	}
+	if b := CreateFromArchetypeAt(newField(1), def, 10, 20); b.ID != a.ID || b.baseSpeed != a.baseSpeed {
+		t.Error("same seed spawned a different agent")
+	}
+	r := CreateFromArchetype(newField(2), def)
+	if r.X < 0 || r.Z < 0 || r.X >= 100 || r.Z >= 100 {
+		t.Errorf("random spawn at (%v, %v) is off the map", r.X, r.Z)
+	}
+}
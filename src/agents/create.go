@@ -3,48 +3,87 @@ package agents
 import (
 	"math"
 	"math/rand"
-	"time"
 	"veatla/simulator/src/utils"
 	worldQuery "veatla/simulator/src/world-query"
 
 	"github.com/google/uuid"
 )
 
+var builtinArchetypes = DefaultArchetypes()
+
+// CreateSimpleAgent creates a peasant at a random free position.
 func CreateSimpleAgent(q worldQuery.WorldQuery) Agent {
-	id := uuid.New()
+	def, _ := builtinArchetypes.Get(DefaultArchetype)
+	return CreateFromArchetype(q, def)
+}
+
+// CreateFromArchetype creates an agent of the given archetype at a random
+// free position.
+func CreateFromArchetype(q worldQuery.WorldQuery, def Archetype) Agent {
+	id := q.NewID()
 	r := rand.New(rand.NewSource(q.GetWorldSeed() + utils.UUIDToInt64(id)))
-	angle := r.Float64() * 2 * math.Pi
 	worldWidth, worldHeight := q.GetBoundaries()
 
 	tx := r.Float64() * worldWidth
 	tz := r.Float64() * worldHeight
 
-	for q.IsPointBlocked(tx, tz) {
+	for q.IsPointBlocked(tx, tz, worldQuery.Passer{Faction: def.Faction}) {
 		tx = r.Float64() * worldWidth
 		tz = r.Float64() * worldHeight
 	}
 
+	return newAgent(q, def, id, r, tx, tz)
+}
+
+// CreateFromArchetypeAt creates an agent of the given archetype at a given
+// position, e.g. a map edge or a house door.
+func CreateFromArchetypeAt(q worldQuery.WorldQuery, def Archetype, x, z float64) Agent {
+	id := q.NewID()
+	r := rand.New(rand.NewSource(q.GetWorldSeed() + utils.UUIDToInt64(id)))
+	return newAgent(q, def, id, r, x, z)
+}
+
+func newAgent(q worldQuery.WorldQuery, def Archetype, id uuid.UUID, r *rand.Rand, tx, tz float64) Agent {
+	angle := r.Float64() * 2 * math.Pi
+
 	agent := Agent{
-		ID:        id,
-		X:         tx,
-		Z:         tz,
-		Width:     1.0,
-		Height:    1.0,
-		VX:        math.Cos(angle),
-		VZ:        math.Sin(angle),
-		baseSpeed: r.Float64()*0.02 + 0.01,
-		changeDirIn: r.Intn(200) + 50,
-		rng:       r,
+		ID:           id,
+		X:            tx,
+		Z:            tz,
+		Width:        def.Width,
+		Height:       def.Height,
+		VX:           math.Cos(angle),
+		VZ:           math.Sin(angle),
+		baseSpeed:    def.MinSpeed + r.Float64()*(def.MaxSpeed-def.MinSpeed),
+		changeDirIn:  def.ChangeDirMin + r.Intn(def.ChangeDirMax-def.ChangeDirMin+1),
+		rng:          r,
+		Archetype:    def.Name,
+		Behaviour:    def.Behaviour,
+		Faction:      def.Faction,
+		Inventory:    Inventory{Capacity: def.CarryCapacity},
+		Money:        def.StartingMoney,
+		Skills:       def.Skills,
+		needRates:    def.Needs,
+		wanderRadius: def.WanderRadius,
+		Happiness:    defaultHappiness,
 		stuck: stuckState{
-			threshold: 100,
-			lastX:    tx,
-			lastZ:    tz,
-		},
-		log: wanderingLog{
-			events:         make([]WanderingEvent, 0),
-			lastWanderTime: time.Now(),
+			threshold: def.StuckThreshold,
+			lastX:     tx,
+			lastZ:     tz,
 		},
 	}
+	agent.Perception.Heading = angle
+	agent.viewRadius, agent.fov = def.ViewRadius, def.FieldOfView*math.Pi/180
+	if agent.viewRadius == 0 {
+		agent.viewRadius = defaultViewRadius
+	}
+	if agent.fov == 0 {
+		agent.fov = defaultFieldOfView * math.Pi / 180
+	}
+	agent.Schedule.Slots = def.Routine
+	if len(agent.Schedule.Slots) == 0 {
+		agent.Schedule.Slots = DefaultRoutine
+	}
 	agent.Wandering = agent.SetWanderingTarget(q)
 	return agent
 }
@@ -0,0 +1,105 @@
+package agents
+
+import (
+	"math"
+
+	worldQuery "veatla/simulator/src/world-query"
+)
+
+const (
+	// goalReach is how close an agent must get to count as arrived at its goal.
+	goalReach = 0.6
+	// maxGoalStuck is how many stuck events an agent tolerates on the way to
+	// a goal before giving it up.
+	maxGoalStuck = 3
+	// maxGoalReplans is how many times in a row an agent replans around a
+	// blocked step without reaching a waypoint before giving up its goal.
+	maxGoalReplans = 20
+)
+
+// goal is a destination set by the world (a job, a schedule) that takes
+// precedence over wandering until it is reached, cleared or found to be
+// unreachable.
+type goal struct {
+	active  bool
+	failed  bool
+	x, z    float64
+	reach   float64
+	stuck   int
+	replans int
+}
+
+// SetGoal sends the agent to (x, z). It must be called between ticks.
+func (agent *Agent) SetGoal(q worldQuery.WorldQuery, x, z float64) {
+	agent.SetGoalWithin(q, x, z, goalReach)
+}
+
+// SetGoalWithin sends the agent to anywhere within reach of (x, z), for
+// places a crowd heads to at once. It must be called between ticks.
+func (agent *Agent) SetGoalWithin(q worldQuery.WorldQuery, x, z, reach float64) {
+	agent.goal = goal{active: true, x: x, z: z, reach: reach}
+	agent.Wandering = Wandering{
+		X:     x,
+		Z:     z,
+		speed: 0.03 + agent.rng.Float64()*0.02,
+	}
+	agent.stuck.counter = 0
+	agent.stuck.lastX = agent.X
+	agent.stuck.lastZ = agent.Z
+	agent.replanGoal(q)
+}
+
+// replanGoal plans a fresh path to the goal, giving it up if there is none.
+func (agent *Agent) replanGoal(q worldQuery.WorldQuery) {
+	path, found := agent.findPath(q, agent.goal.x, agent.goal.z)
+	if !found {
+		agent.failGoal()
+		return
+	}
+	agent.path.path = path
+	agent.NoPath = false
+	if len(path) > 1 {
+		agent.path.pathIndex = 1
+	} else {
+		agent.path.pathIndex = 0
+	}
+}
+
+// ClearGoal returns the agent to its default behaviour.
+func (agent *Agent) ClearGoal() {
+	agent.goal = goal{}
+	agent.Wandering.wait = 0
+}
+
+// HasGoal reports whether the agent is heading somewhere on purpose.
+func (agent *Agent) HasGoal() bool { return agent.goal.active }
+
+// GoalFailed reports whether the last goal was dropped as unreachable.
+// It stays set until the next SetGoal or ClearGoal.
+func (agent *Agent) GoalFailed() bool { return agent.goal.failed }
+
+// AtGoal reports whether the agent has arrived at its goal.
+func (agent *Agent) AtGoal() bool {
+	if !agent.goal.active {
+		return false
+	}
+	return math.Hypot(agent.goal.x-agent.X, agent.goal.z-agent.Z) < agent.goal.reach
+}
+
+func (agent *Agent) failGoal() {
+	agent.goal = goal{failed: true}
+	agent.path.path = nil
+	agent.path.pathIndex = 0
+	agent.NoPath = true
+	agent.Wandering.wait = 0
+}
+
+// retarget is called when the current target cannot be reached: a goal is
+// given up, a wandering target is replaced by one within radius.
+func (agent *Agent) retarget(q worldQuery.WorldQuery, radius float64) {
+	if agent.goal.active {
+		agent.failGoal()
+		return
+	}
+	agent.Wandering = agent.setWanderingTargetWithRadius(q, radius)
+}
@@ -0,0 +1,52 @@
+package agents
+
+import (
+	"testing"
+	"time"
+)
+
+func walker(t *testing.T, f *field) *Agent {
+	t.Helper()
+	def, _ := DefaultArchetypes().Get(DefaultArchetype)
+	a := CreateFromArchetypeAt(f, def, 10, 50)
+	return &a
+}
+
+func TestGoalReplansAroundNewWall(t *testing.T) {
+	f := newField(1)
+	a := walker(t, f)
+	a.SetGoal(f, 40, 50)
+	if !a.HasGoal() || a.GoalFailed() {
+		t.Fatal("no path across an open field")
+	}
+
+	// A wall goes up across the planned path, leaving a way round its end.
+	f.wall = func(x, z float64) bool { return x >= 20 && x < 22 && z >= 30 && z < 70 }
+	for range 20000 {
+		a.Tick(50*time.Millisecond, f)
+		if a.AtGoal() || a.GoalFailed() {
+			break
+		}
+	}
+	if !a.AtGoal() {
+		t.Fatalf("agent at (%.1f, %.1f) never reached its goal; failed %v", a.X, a.Z, a.GoalFailed())
+	}
+	if a.Stats().PathRequests < 2 {
+		t.Errorf("%d path requests, want a replan", a.Stats().PathRequests)
+	}
+}
+
+func TestGoalFailsWhenWalledOff(t *testing.T) {
+	f := newField(2)
+	a := walker(t, f)
+	a.SetGoal(f, 40, 50)
+
+	f.wall = func(x, z float64) bool { return x >= 20 && x < 22 }
+	a.Replan(f)
+	if !a.GoalFailed() || a.HasGoal() {
+		t.Fatal("goal behind a wall across the whole map was kept")
+	}
+	if !a.NoPath || len(a.PathAhead()) != 0 {
+		t.Error("failed goal left a path behind")
+	}
+}
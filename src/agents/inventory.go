@@ -0,0 +1,138 @@
+package agents
+
+import (
+	"math"
+
+	"veatla/simulator/src/goods"
+)
+
+// loadPenalty is the fraction of speed lost when carrying a full load.
+const loadPenalty = 0.5
+
+// Inventory holds the goods an agent carries, limited by total weight.
+type Inventory struct {
+	Capacity float64
+	Items    goods.Stock
+}
+
+// Weight returns the weight currently carried.
+func (inv *Inventory) Weight() float64 {
+	return inv.Items.Weight()
+}
+
+// Count returns the units of g carried.
+func (inv *Inventory) Count(g goods.Good) int {
+	return inv.Items.Count(g)
+}
+
+// Load returns the carried weight as a fraction of capacity in [0, 1].
+func (inv *Inventory) Load() float64 {
+	if inv.Capacity <= 0 {
+		return 0
+	}
+	return math.Min(inv.Weight()/inv.Capacity, 1)
+}
+
+// Room returns how many more units of g fit.
+func (inv *Inventory) Room(g goods.Good) int {
+	free := inv.Capacity - inv.Weight()
+	if free <= 0 {
+		return 0
+	}
+	return int(free / goods.Weight(g))
+}
+
+// Add stores up to n units of g and returns how many fit.
+func (inv *Inventory) Add(g goods.Good, n int) int {
+	n = min(n, inv.Room(g))
+	if n <= 0 {
+		return 0
+	}
+	if inv.Items == nil {
+		inv.Items = goods.Stock{}
+	}
+	inv.Items.Add(g, n)
+	return n
+}
+
+// Take removes up to n units of g and returns how many were removed.
+func (inv *Inventory) Take(g goods.Good, n int) int {
+	if inv.Items == nil {
+		return 0
+	}
+	return inv.Items.Take(g, n)
+}
+
+// Empty reports whether nothing is carried.
+func (inv *Inventory) Empty() bool {
+	return len(inv.Items) == 0
+}
+
+// Clear empties the inventory and returns what it held.
+func (inv *Inventory) Clear() goods.Stock {
+	out := inv.Items
+	inv.Items = nil
+	return out
+}
+
+// speed is the agent's current step length, slowed by the carried load.
+func (agent *Agent) speed() float64 {
+	return (agent.baseSpeed + agent.Wandering.speed) * (1 - loadPenalty*agent.Inventory.Load())
+}
+
+// nourishment is how much hunger one unit of a food good satisfies.
+var nourishment = map[goods.Good]float64{
+	goods.Bread: 0.5,
+	goods.Grain: 0.2,
+}
+
+// hungryAbove is the hunger level at which an agent seeks food.
+const hungryAbove = 0.4
+
+// Hungry reports whether the agent should look for food.
+func (agent *Agent) Hungry() bool {
+	return agent.Needs.Hunger >= hungryAbove
+}
+
+// IsFood reports whether g can be eaten.
+func IsFood(g goods.Good) bool {
+	_, ok := nourishment[g]
+	return ok
+}
+
+// Eat consumes one unit of the most nourishing food carried, if the agent is
+// hungry, and reports whether it ate.
+func (agent *Agent) Eat() bool {
+	if !agent.Hungry() {
+		return false
+	}
+	for _, g := range []goods.Good{goods.Bread, goods.Grain} {
+		if agent.Inventory.Take(g, 1) == 1 {
+			agent.Needs.Hunger = math.Max(agent.Needs.Hunger-nourishment[g], 0)
+			return true
+		}
+	}
+	return false
+}
+
+// luxuries is how much happiness one unit of a luxury good brings.
+var luxuries = map[goods.Good]float64{
+	goods.Cloth: 0.05,
+}
+
+// IsLuxury reports whether g is consumed for happiness.
+func IsLuxury(g goods.Good) bool {
+	_, ok := luxuries[g]
+	return ok
+}
+
+// Enjoy consumes one luxury good carried and reports whether it did.
+func (agent *Agent) Enjoy() bool {
+	for _, g := range []goods.Good{goods.Cloth} {
+		if agent.Inventory.Take(g, 1) == 1 {
+			agent.Happiness = math.Min(agent.Happiness+luxuries[g], 1)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package agents
+
+import (
+	"testing"
+
+	"veatla/simulator/src/goods"
+)
+
+func TestInventoryCapacity(t *testing.T) {
+	inv := Inventory{Capacity: 10}
+	// Wood weighs 2 and stone 3.
+	if n := inv.Add(goods.Wood, 3); n != 3 {
+		t.Fatalf("added %d wood, want 3", n)
+	}
+	if n := inv.Add(goods.Stone, 5); n != 1 {
+		t.Fatalf("added %d stone, want the 1 that fits", n)
+	}
+	if inv.Weight() != 9 || inv.Room(goods.Wood) != 0 || inv.Load() != 0.9 {
+		t.Fatalf("weight %v, room %d, load %v", inv.Weight(), inv.Room(goods.Wood), inv.Load())
+	}
+	if n := inv.Take(goods.Wood, 5); n != 3 || inv.Count(goods.Wood) != 0 {
+		t.Fatalf("took %d wood, %d left", n, inv.Count(goods.Wood))
+	}
+	if got := inv.Clear(); got.Count(goods.Stone) != 1 || !inv.Empty() {
+		t.Fatalf("cleared %v, empty %v", got, inv.Empty())
+	}
+	if n := inv.Take(goods.Stone, 1); n != 0 {
+		t.Fatalf("took %d from an empty inventory", n)
+	}
+}
+
+func TestLoadSlowsAgent(t *testing.T) {
+	a := Agent{baseSpeed: 1, Inventory: Inventory{Capacity: 4}}
+	if s := a.speed(); s != 1 {
+		t.Fatalf("unloaded speed %v, want 1", s)
+	}
+	a.Inventory.Add(goods.Wood, 2)
+	if s := a.speed(); s != 1-loadPenalty {
+		t.Fatalf("fully loaded speed %v, want %v", s, 1-loadPenalty)
+	}
+}
@@ -0,0 +1,88 @@
+package agents
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// PlaceKind is what an agent remembers a place as.
+type PlaceKind string
+
+const (
+	// PlaceStore is a stockpile or storage zone.
+	PlaceStore  PlaceKind = "store"
+	PlaceMarket PlaceKind = "market"
+	PlaceHouse  PlaceKind = "house"
+	// PlaceWorkplace is any other building that employs workers.
+	PlaceWorkplace PlaceKind = "workplace"
+	// PlaceNode is a tree, rock or vein to gather from, PlaceField a farm
+	// field and PlaceSite a construction site.
+	PlaceNode  PlaceKind = "node"
+	PlaceField PlaceKind = "field"
+	PlaceSite  PlaceKind = "site"
+	// PlaceDanger is somewhere the agent saw someone die.
+	PlaceDanger PlaceKind = "danger"
+)
+
+// Place is a remembered location.
+type Place struct {
+	Kind PlaceKind `json:"kind"`
+	X    float64   `json:"x"`
+	Z    float64   `json:"z"`
+	// Certainty is 1 when the place was last seen and fades to 0, when it is
+	// forgotten.
+	Certainty float64 `json:"certainty"`
+}
+
+// dangerRadius is how far from a remembered danger an agent prefers not to
+// wander.
+const dangerRadius = 3.0
+
+// Memory is what an agent knows of the world: the places it has seen and
+// not yet forgotten. Agents know nothing they have not perceived.
+type Memory struct {
+	Places map[uuid.UUID]Place
+}
+
+// See records or refreshes a place.
+func (m *Memory) See(id uuid.UUID, kind PlaceKind, x, z float64) {
+	if m.Places == nil {
+		m.Places = map[uuid.UUID]Place{}
+	}
+	m.Places[id] = Place{Kind: kind, X: x, Z: z, Certainty: 1}
+}
+
+// Knows reports whether the agent remembers a place.
+func (m *Memory) Knows(id uuid.UUID) bool {
+	_, ok := m.Places[id]
+	return ok
+}
+
+// Forget drops a place, e.g. one found to be gone.
+func (m *Memory) Forget(id uuid.UUID) {
+	delete(m.Places, id)
+}
+
+// Fade lowers the certainty of every place by amount and forgets those that
+// reach 0.
+func (m *Memory) Fade(amount float64) {
+	for id, p := range m.Places {
+		p.Certainty -= amount
+		if p.Certainty <= 0 {
+			delete(m.Places, id)
+			continue
+		}
+		m.Places[id] = p
+	}
+}
+
+// NearDanger reports whether (x, z) is within r of a remembered danger.
+func (m *Memory) NearDanger(x, z, r float64) bool {
+	for _, p := range m.Places {
+		if p.Kind == PlaceDanger && math.Hypot(p.X-x, p.Z-z) <= r {
+			return true
+		}
+	}
+	return false
+}
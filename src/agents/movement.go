@@ -2,7 +2,6 @@ package agents
 
 import (
 	"math"
-	navgrid "veatla/simulator/src/nav-grid"
 	"veatla/simulator/src/utils"
 	worldQuery "veatla/simulator/src/world-query"
 )
@@ -15,25 +14,27 @@ func (agent *Agent) MoveTorwardsWanderingTarget(q worldQuery.WorldQuery) {
 		dist := math.Sqrt(dx*dx + dz*dz)
 		if dist < 0.2 {
 			agent.path.pathIndex++
+			agent.goal.replans = 0
 			return
 		}
 
 		dx /= dist
 		dz /= dist
-		agent.VX = dx * (agent.baseSpeed + agent.Wandering.speed)
-		agent.VZ = dz * (agent.baseSpeed + agent.Wandering.speed)
+		step := agent.speed() / q.MoveCost(agent.X, agent.Z)
+		agent.VX = dx * step
+		agent.VZ = dz * step
 		agent.NoPath = false
 		nextX := agent.X + agent.VX
 		nextZ := agent.Z + agent.VZ
 
-		if !q.IsPointBlocked(nextX, nextZ) {
+		if !q.IsPointBlocked(nextX, nextZ, agent.Passer()) {
 			worldWidth, worldHeight := q.GetBoundaries()
 			agent.X = utils.Clamp(nextX, 0, worldWidth)
 			agent.Z = utils.Clamp(nextZ, 0, worldHeight)
 			return
 		}
 
-		if !q.IsPointBlocked(target.X, target.Z) {
+		if !q.IsPointBlocked(target.X, target.Z, agent.Passer()) {
 			worldWidth, worldHeight := q.GetBoundaries()
 			agent.X = utils.Clamp(target.X, 0, worldWidth)
 			agent.Z = utils.Clamp(target.Z, 0, worldHeight)
@@ -55,10 +56,10 @@ func (agent *Agent) MoveTorwardsWanderingTarget(q worldQuery.WorldQuery) {
 	}
 	dx /= dist
 	dz /= dist
-	step := agent.baseSpeed + agent.Wandering.speed
+	step := agent.speed() / q.MoveCost(agent.X, agent.Z)
 	nextX := agent.X + dx*step
 	nextZ := agent.Z + dz*step
-	if !q.IsPointBlocked(nextX, nextZ) {
+	if !q.IsPointBlocked(nextX, nextZ, agent.Passer()) {
 		worldWidth, worldHeight := q.GetBoundaries()
 		agent.X = utils.Clamp(nextX, 0, worldWidth)
 		agent.Z = utils.Clamp(nextZ, 0, worldHeight)
@@ -70,16 +71,16 @@ func (agent *Agent) MoveTorwardsWanderingTarget(q worldQuery.WorldQuery) {
 }
 
 func (agent *Agent) navigateWithAStar(q worldQuery.WorldQuery) {
-	const obstacleOffset = 1.0
-
-	path, found, _ := navgrid.AStarPath(
-		agent.X, agent.Z,
-		agent.Wandering.X, agent.Wandering.Z,
-		q, obstacleOffset,
-	)
-
-	if !found || len(path) == 0 {
-		agent.Wandering = agent.SetWanderingTarget(q)
+	if agent.goal.active {
+		agent.goal.replans++
+		if agent.goal.replans > maxGoalReplans {
+			agent.failGoal()
+			return
+		}
+	}
+	path, found := agent.findPath(q, agent.Wandering.X, agent.Wandering.Z)
+	if !found {
+		agent.retarget(q, agent.wanderRadius)
 		agent.stuck.counter = 0
 		agent.stuck.lastX = agent.X
 		agent.stuck.lastZ = agent.Z
@@ -0,0 +1,59 @@
+package agents
+
+import (
+	"veatla/simulator/src/events"
+	navgrid "veatla/simulator/src/nav-grid"
+	worldQuery "veatla/simulator/src/world-query"
+)
+
+// PathAhead returns the waypoints the agent has yet to reach.
+func (agent *Agent) PathAhead() []navgrid.PathPoint {
+	if agent.path.pathIndex >= len(agent.path.path) {
+		return nil
+	}
+	return agent.path.path[agent.path.pathIndex:]
+}
+
+// Replan drops the current path and plans a new one to wherever the agent is
+// heading, e.g. after a gate on the way was closed. It must be called between
+// ticks.
+func (agent *Agent) Replan(q worldQuery.WorldQuery) {
+	agent.path.path = nil
+	agent.path.pathIndex = 0
+	if agent.goal.active {
+		agent.replanGoal(q)
+		return
+	}
+	path, found := agent.findPath(q, agent.Wandering.X, agent.Wandering.Z)
+	if !found {
+		agent.retarget(q, agent.wanderRadius)
+		return
+	}
+	agent.path.path = path
+	agent.NoPath = false
+	if len(path) > 1 {
+		agent.path.pathIndex = 1
+	}
+}
+
+// findPath runs A* from the agent's position to (tx, tz) and records the
+// attempt in the agent's stats.
+func (agent *Agent) findPath(q worldQuery.WorldQuery, tx, tz float64) ([]navgrid.PathPoint, bool) {
+	const obstacleOffset = 1.0
+
+	agent.stats.PathRequests++
+	path, found, _, stats := navgrid.AStarPathWithStats(agent.X, agent.Z, tx, tz, q, agent.Passer(), obstacleOffset)
+	agent.stats.PathExpansions += stats.Expansions
+	if !found || len(path) == 0 {
+		agent.stats.PathFailures++
+		q.Emit(events.PathFailed{
+			AgentID: agent.ID,
+			FromX:   agent.X,
+			FromZ:   agent.Z,
+			TargetX: tx,
+			TargetZ: tz,
+		})
+		return nil, false
+	}
+	return path, true
+}
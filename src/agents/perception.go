@@ -0,0 +1,72 @@
+package agents
+
+import (
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultViewRadius and defaultFieldOfView apply to archetypes that do
+	// not set their own. The field of view is in degrees.
+	defaultViewRadius  = 12.0
+	defaultFieldOfView = 120.0
+)
+
+// Perception is what an agent sees. The world refreshes it once a tick,
+// before agents decide, so behaviours such as fleeing, greeting or guarding
+// can read it without querying the world.
+type Perception struct {
+	// Tick is the tick the sets were gathered on.
+	Tick int
+	// Heading is the direction the agent faces, as atan2(VZ, VX). It is kept
+	// while the agent stands still.
+	Heading float64
+	// Agents are the other agents in view.
+	Agents []uuid.UUID
+	// Objects are the buildings, walls, gates and resource nodes in view.
+	// Closed gates hide what is behind them.
+	Objects []uuid.UUID
+}
+
+// SeesAgent reports whether another agent is in view.
+func (p *Perception) SeesAgent(id uuid.UUID) bool {
+	return slices.Contains(p.Agents, id)
+}
+
+// SeesObject reports whether an obstacle is in view.
+func (p *Perception) SeesObject(id uuid.UUID) bool {
+	return slices.Contains(p.Objects, id)
+}
+
+// Eye is the point the agent sees from, the centre of its footprint.
+func (agent *Agent) Eye() (float64, float64) {
+	return agent.X + agent.Width/2, agent.Z + agent.Height/2
+}
+
+// ViewRadius is how far the agent sees.
+func (agent *Agent) ViewRadius() float64 { return agent.viewRadius }
+
+// FaceMovement turns the agent's heading to its velocity, if it is moving.
+func (agent *Agent) FaceMovement() {
+	if agent.VX != 0 || agent.VZ != 0 {
+		agent.Perception.Heading = math.Atan2(agent.VZ, agent.VX)
+	}
+}
+
+// InView reports whether (x, z) is within the agent's view radius and field
+// of view. It does not check for anything in the way.
+func (agent *Agent) InView(x, z float64) bool {
+	ex, ez := agent.Eye()
+	dx, dz := x-ex, z-ez
+	d := math.Hypot(dx, dz)
+	if d > agent.viewRadius {
+		return false
+	}
+	if d < 1e-9 || agent.fov >= 2*math.Pi {
+		return true
+	}
+	off := math.Abs(math.Remainder(math.Atan2(dz, dx)-agent.Perception.Heading, 2*math.Pi))
+	return off <= agent.fov/2
+}
@@ -0,0 +1,71 @@
+package agents
+
+import "fmt"
+
+// Activity is what a schedule has an agent doing at some time of day.
+type Activity string
+
+const (
+	ActivityFree   Activity = "free"
+	ActivityHome   Activity = "home"
+	ActivityWork   Activity = "work"
+	ActivityMarket Activity = "market"
+	ActivityTavern Activity = "tavern"
+)
+
+// Slot is one window of a daily routine. A slot whose To is before its From
+// wraps past midnight.
+type Slot struct {
+	From     float64  `json:"from"`
+	To       float64  `json:"to"`
+	Activity Activity `json:"activity"`
+}
+
+func (s Slot) covers(hour float64) bool {
+	if s.From <= s.To {
+		return hour >= s.From && hour < s.To
+	}
+	return hour >= s.From || hour < s.To
+}
+
+// Schedule is an agent's daily routine. Hours it does not cover are free
+// time, spent wandering.
+type Schedule struct {
+	// Slots is shared with the archetype definition and must not be modified.
+	Slots []Slot
+	// Current is the activity the world last started the agent on.
+	Current Activity
+}
+
+// DefaultRoutine takes an agent from home to work, then the market and the
+// tavern, and back home for the night.
+var DefaultRoutine = []Slot{
+	{From: 20, To: 6, Activity: ActivityHome},
+	{From: 7, To: 15, Activity: ActivityWork},
+	{From: 15, To: 17, Activity: ActivityMarket},
+	{From: 17, To: 20, Activity: ActivityTavern},
+}
+
+// At returns what the schedule has the agent doing at hour.
+func (s *Schedule) At(hour float64) Activity {
+	for _, slot := range s.Slots {
+		if slot.covers(hour) {
+			return slot.Activity
+		}
+	}
+	return ActivityFree
+}
+
+func validateRoutine(slots []Slot) error {
+	for _, s := range slots {
+		if s.From < 0 || s.From >= 24 || s.To < 0 || s.To > 24 {
+			return fmt.Errorf("slot %v-%v is outside the day", s.From, s.To)
+		}
+		switch s.Activity {
+		case ActivityFree, ActivityHome, ActivityWork, ActivityMarket, ActivityTavern:
+		default:
+			return fmt.Errorf("unknown activity %q", s.Activity)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package agents
+
+import "testing"
+
+func TestScheduleAt(t *testing.T) {
+	s := Schedule{Slots: DefaultRoutine}
+	for hour, want := range map[float64]Activity{
+		22:  ActivityHome,
+		3:   ActivityHome,
+		6.5: ActivityFree,
+		7:   ActivityWork,
+		15:  ActivityMarket,
+		19:  ActivityTavern,
+		20:  ActivityHome,
+	} {
+		if got := s.At(hour); got != want {
+			t.Errorf("at %v: %s, want %s", hour, got, want)
+		}
+	}
+	if got := (&Schedule{}).At(12); got != ActivityFree {
+		t.Errorf("empty routine at noon: %s, want %s", got, ActivityFree)
+	}
+}
+
+func TestValidateRoutine(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		slots []Slot
+		ok    bool
+	}{
+		{"default", DefaultRoutine, true},
+		{"wraps", []Slot{{From: 22, To: 2, Activity: ActivityTavern}}, true},
+		{"late", []Slot{{From: 24, To: 2, Activity: ActivityHome}}, false},
+		{"negative", []Slot{{From: -1, To: 2, Activity: ActivityHome}}, false},
+		{"unknown", []Slot{{From: 1, To: 2, Activity: "duel"}}, false},
+	} {
+		if err := validateRoutine(tc.slots); (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v", tc.name, err)
+		}
+	}
+}
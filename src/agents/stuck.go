@@ -1,10 +1,9 @@
 package agents
 
 import (
-	"log"
 	"math"
+	"veatla/simulator/src/events"
 	worldQuery "veatla/simulator/src/world-query"
-	navgrid "veatla/simulator/src/nav-grid"
 )
 
 func (agent *Agent) detectStuck(q worldQuery.WorldQuery) {
@@ -32,16 +31,24 @@ func (agent *Agent) detectStuck(q worldQuery.WorldQuery) {
 	if distance < epsilon {
 		agent.stuck.counter++
 		if agent.stuck.counter > agent.stuck.threshold {
-			log.Printf("Agent %s is STUCK at position (%.2f, %.2f) for %d ticks with distance %.2f",
-				agent.ID.String()[:8], agent.X, agent.Z, agent.stuck.counter,
-				distance,
-			)
+			agent.stats.StuckEvents++
+			q.Emit(events.AgentStuck{
+				AgentID: agent.ID,
+				X:       agent.X,
+				Z:       agent.Z,
+				Ticks:   agent.stuck.counter,
+			})
 
-			if agent.stuck.counter%replanCooldown == 0 {
-				const obstacleOffset = 1.0
+			if agent.goal.active {
+				agent.goal.stuck++
+				if agent.goal.stuck > maxGoalStuck {
+					agent.failGoal()
+				} else {
+					agent.replanGoal(q)
+				}
+			} else if agent.stuck.counter%replanCooldown == 0 {
 				if agent.Wandering.X != agent.X || agent.Wandering.Z != agent.Z {
-					path, found, _ := navgrid.AStarPath(agent.X, agent.Z, agent.Wandering.X, agent.Wandering.Z, q, obstacleOffset)
-					if found && len(path) > 0 {
+					if path, found := agent.findPath(q, agent.Wandering.X, agent.Wandering.Z); found {
 						agent.path.path = path
 						if len(path) > 1 {
 							agent.path.pathIndex = 1
@@ -49,7 +56,7 @@ func (agent *Agent) detectStuck(q worldQuery.WorldQuery) {
 							agent.path.pathIndex = 0
 						}
 					} else {
-						agent.Wandering = agent.setWanderingTargetWithRadius(q, 8.0)
+						agent.retarget(q, 8.0)
 					}
 				}
 			}
@@ -4,13 +4,23 @@ import (
 	"math"
 	"time"
 	worldQuery "veatla/simulator/src/world-query"
+
+	"github.com/google/uuid"
 )
 
 func (agent *Agent) Tick(dt time.Duration, q worldQuery.WorldQuery) bool {
 	oldX, oldZ := agent.X, agent.Z
+	agent.Age += dt
+	agent.advanceNeeds(dt, q)
+
+	// Agents sleep through the night once they are where the world sent
+	// them, or wherever they are if it sent them nowhere.
+	if q.Now().Night && (!agent.goal.active || agent.AtGoal()) {
+		agent.sleep(dt, q)
+		return false
+	}
 
-	if agent.Wandering.wait <= 0 {
-		agent.logWanderingEvent(q)
+	if agent.Wandering.wait <= 0 && !agent.goal.active {
 		agent.Wandering = agent.SetWanderingTarget(q)
 	}
 
@@ -19,8 +29,10 @@ func (agent *Agent) Tick(dt time.Duration, q worldQuery.WorldQuery) bool {
 	dist2 := dx*dx + dz*dz
 	const reachDist = 0.5
 
-	if dist2 < reachDist*reachDist {
-		agent.Wandering.wait -= dt
+	if dist2 < reachDist*reachDist || agent.AtGoal() {
+		if !agent.goal.active {
+			agent.Wandering.wait -= dt
+		}
 		return math.Abs(oldX-agent.X) > 1e-9 || math.Abs(oldZ-agent.Z) > 1e-9
 	}
 
@@ -29,3 +41,37 @@ func (agent *Agent) Tick(dt time.Duration, q worldQuery.WorldQuery) bool {
 
 	return math.Abs(oldX-agent.X) > 1e-9 || math.Abs(oldZ-agent.Z) > 1e-9
 }
+
+func (agent *Agent) advanceNeeds(dt time.Duration, q worldQuery.WorldQuery) {
+	day := float64(q.DayLength())
+	if day <= 0 {
+		return
+	}
+	frac := float64(dt) / day
+	agent.Needs.Hunger = math.Min(agent.Needs.Hunger+agent.needRates.Hunger*frac, 1)
+	agent.Needs.Rest = math.Min(agent.Needs.Rest+agent.needRates.Rest*frac, 1)
+}
+
+// sleepRecovery is how much rest a sleeping agent recovers per day in its
+// own bed; sleeping rough recovers roughSleep of that.
+const (
+	sleepRecovery = 3.0
+	roughSleep    = 0.5
+)
+
+func (agent *Agent) sleep(dt time.Duration, q worldQuery.WorldQuery) {
+	agent.VX, agent.VZ = 0, 0
+	agent.Wandering.wait = 0
+	if day := float64(q.DayLength()); day > 0 {
+		rate := sleepRecovery
+		if !agent.AtHome() {
+			rate *= roughSleep
+		}
+		agent.Needs.Rest = math.Max(agent.Needs.Rest-rate*float64(dt)/day, 0)
+	}
+}
+
+// AtHome reports whether the world sent the agent home and it got there.
+func (agent *Agent) AtHome() bool {
+	return agent.Home != uuid.Nil && agent.Schedule.Current == ActivityHome && agent.goal.active && agent.AtGoal()
+}
@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"time"
 	navgrid "veatla/simulator/src/nav-grid"
+	worldQuery "veatla/simulator/src/world-query"
 
 	"github.com/google/uuid"
 )
@@ -22,31 +23,90 @@ type stuckState struct {
 	lastReplanTick int
 }
 
-// wanderingLog holds event log and last wander time
-type wanderingLog struct {
-	events         []WanderingEvent
-	lastWanderTime time.Time
+// Stats counts pathfinding and stuck events over the agent's lifetime.
+type Stats struct {
+	PathRequests   int
+	PathFailures   int
+	PathExpansions int
+	StuckEvents    int
 }
 
 // Agent is the main agent type: position, velocity, wandering target and internal state.
 type Agent struct {
-	X, Z         float64
-	ID           uuid.UUID
-	VX, VZ       float64
+	X, Z          float64
+	ID            uuid.UUID
+	VX, VZ        float64
 	Width, Height float64
-	changeDirIn  int
-	baseSpeed    float64
-	rng          *rand.Rand
+	changeDirIn   int
+	baseSpeed     float64
+	rng           *rand.Rand
 	Wandering
 
-	path pathState
+	path  pathState
 	stuck stuckState
-	log  wanderingLog
+	stats Stats
+	goal  goal
 
 	// NoPath is set when A* fails to find a path to current target (used by websocket etc.)
 	NoPath bool
+
+	// Age is how long the agent has been alive; it dies of old age once Age
+	// reaches Lifespan. A zero Lifespan means the agent never ages out.
+	Age      time.Duration
+	Lifespan time.Duration
+	// Happiness in [0, 1] drives immigration and emigration.
+	Happiness float64
+
+	// Archetype is the name of the definition the agent was spawned from.
+	Archetype string
+	Behaviour Behaviour
+	// Faction decides which closed gates the agent may pass.
+	Faction string
+	// Inventory holds carried goods; its capacity comes from the archetype.
+	// Like Memory, it is only changed by the world between agent ticks.
+	Inventory Inventory
+	// Money is the coins the agent owns.
+	Money int64
+	// Workplace is the building the agent works at, or uuid.Nil.
+	Workplace uuid.UUID
+	// Household is the household the agent belongs to and Home the house it
+	// lives in; both are uuid.Nil for the homeless.
+	Household uuid.UUID
+	Home      uuid.UUID
+	// Memory holds the places the agent knows of. Tick may read it but not
+	// write it: agents tick on shallow copies that share it.
+	Memory Memory
+	// Perception is what the agent sees this tick.
+	Perception Perception
+	// Schedule is the agent's daily routine.
+	Schedule Schedule
+	// Skills is shared with the archetype definition and must not be modified.
+	Skills       map[string]float64
+	Needs        Needs
+	needRates    NeedRates
+	wanderRadius float64
+	viewRadius   float64
+	// fov is the field of view in radians.
+	fov float64
+}
+
+// Needs are in [0, 1]; 0 is fully satisfied.
+type Needs struct {
+	Hunger float64
+	Rest   float64
+}
+
+// defaultHappiness is the happiness a new agent starts with.
+const defaultHappiness = 0.7
+
+// Passer identifies the agent to IsPointBlocked.
+func (a *Agent) Passer() worldQuery.Passer {
+	return worldQuery.Passer{ID: a.ID, Faction: a.Faction}
 }
 
+// Stats returns the agent's pathfinding and stuck counters.
+func (a *Agent) Stats() Stats { return a.stats }
+
 // GetPath returns the current computed path.
 func (a *Agent) GetPath() []navgrid.PathPoint { return a.path.path }
 
@@ -57,19 +117,3 @@ type Wandering struct {
 	wait  time.Duration
 	speed float64
 }
-
-// WanderingEvent logs when an agent wanders.
-type WanderingEvent struct {
-	Timestamp        time.Time
-	X, Z             float64
-	TargetX, TargetZ float64
-	Duration         time.Duration
-}
-
-// StuckEvent logs when an agent gets stuck.
-type StuckEvent struct {
-	Timestamp time.Time
-	X, Z      float64
-	Duration  time.Duration
-}
-
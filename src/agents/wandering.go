@@ -3,13 +3,13 @@ package agents
 import (
 	"math"
 	"time"
-	navgrid "veatla/simulator/src/nav-grid"
+	"veatla/simulator/src/events"
 	"veatla/simulator/src/utils"
 	worldQuery "veatla/simulator/src/world-query"
 )
 
 func (agent *Agent) SetWanderingTarget(q worldQuery.WorldQuery) Wandering {
-	return agent.setWanderingTargetWithRadius(q, 30.0)
+	return agent.setWanderingTargetWithRadius(q, agent.wanderRadius)
 }
 
 func (agent *Agent) setWanderingTargetWithRadius(q worldQuery.WorldQuery, maxRadius float64) Wandering {
@@ -26,7 +26,11 @@ func (agent *Agent) setWanderingTargetWithRadius(q worldQuery.WorldQuery, maxRad
 
 	worldWidth, worldHeight := q.GetBoundaries()
 
-	for tx < 0 || tx > worldWidth || tz < 0 || tz > worldHeight || q.IsPointBlocked(tx, tz) {
+	// Agents steer clear of dangers they remember, unless every try lands
+	// near one.
+	const dangerTries = 5
+	for tries := 0; tx < 0 || tx > worldWidth || tz < 0 || tz > worldHeight || q.IsPointBlocked(tx, tz, agent.Passer()) ||
+		(tries < dangerTries && agent.Memory.NearDanger(tx, tz, dangerRadius)); tries++ {
 		angle = agent.rng.Float64() * 2 * math.Pi
 		radius = math.Sqrt(agent.rng.Float64()) * maxRadius
 		dx = math.Cos(angle) * radius
@@ -36,7 +40,7 @@ func (agent *Agent) setWanderingTargetWithRadius(q worldQuery.WorldQuery, maxRad
 	}
 
 	var targetX, targetZ float64
-	if offsetX, offsetZ, ok := utils.FindOffsetPosition(tx, tz, obstacleOffset, q); ok {
+	if offsetX, offsetZ, ok := utils.FindOffsetPosition(tx, tz, obstacleOffset, q, agent.Passer()); ok {
 		targetX = offsetX
 		targetZ = offsetZ
 	} else {
@@ -44,7 +48,7 @@ func (agent *Agent) setWanderingTargetWithRadius(q worldQuery.WorldQuery, maxRad
 		targetZ = utils.Clamp(tz, 0, worldHeight)
 	}
 
-	if path, found, _ := navgrid.AStarPath(agent.X, agent.Z, targetX, targetZ, q, obstacleOffset); found && len(path) > 0 {
+	if path, found := agent.findPath(q, targetX, targetZ); found {
 		agent.path.path = path
 		agent.NoPath = false
 		if len(path) > 1 {
@@ -58,6 +62,14 @@ func (agent *Agent) setWanderingTargetWithRadius(q worldQuery.WorldQuery, maxRad
 		agent.path.pathIndex = 0
 	}
 
+	q.Emit(events.TargetChosen{
+		AgentID: agent.ID,
+		FromX:   agent.X,
+		FromZ:   agent.Z,
+		TargetX: targetX,
+		TargetZ: targetZ,
+	})
+
 	return Wandering{
 		speed: 0.03 + agent.rng.Float64()*0.02,
 		X:     targetX,
@@ -0,0 +1,115 @@
+package batch
+
+import (
+	"sync"
+	"time"
+
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/scenario"
+
+	"github.com/google/uuid"
+)
+
+// Config controls a headless batch run.
+type Config struct {
+	Seeds    []int64
+	Days     float64
+	Step     time.Duration
+	Parallel int
+	// Scenario builds the starting scenario for a seed.
+	Scenario func(seed int64) scenario.Config
+}
+
+// Result is the end-of-run summary for one seed.
+type Result struct {
+	Seed         int64   `json:"seed"`
+	Days         float64 `json:"days"`
+	Ticks        int     `json:"ticks"`
+	Population   int     `json:"population"`
+	StuckAgents  int     `json:"stuckAgents"`
+	StuckEvents  int     `json:"stuckEvents"`
+	PathRequests int     `json:"pathRequests"`
+	PathFailures int     `json:"pathFailures"`
+	// MoneySupply is the coins held in the world at the end of the run and
+	// MoneyDrift its difference from what the ledger accounts for; anything
+	// but zero means money was created or destroyed outside the rules.
+	MoneySupply int64              `json:"moneySupply"`
+	MoneyDrift  int64              `json:"moneyDrift"`
+	Treasury    int64              `json:"treasury"`
+	Production  map[string]int     `json:"production"`
+	Prices      map[string]float64 `json:"prices"`
+	WallTime    time.Duration      `json:"wallTimeNs"`
+}
+
+// Run simulates every seed for cfg.Days and returns results in seed order.
+// Seeds run concurrently on up to cfg.Parallel workers; each seed owns its
+// world so runs do not share state.
+func Run(cfg Config) []Result {
+	if cfg.Scenario == nil {
+		cfg.Scenario = scenario.Default
+	}
+	workers := max(cfg.Parallel, 1)
+
+	results := make([]Result, len(cfg.Seeds))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runSeed(cfg, cfg.Seeds[i])
+			}
+		}()
+	}
+	for i := range cfg.Seeds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func runSeed(cfg Config, seed int64) Result {
+	start := time.Now()
+	w := scenario.Build(cfg.Scenario(seed))
+
+	res := Result{
+		Seed:       seed,
+		Days:       cfg.Days,
+		Production: map[string]int{},
+		Prices:     map[string]float64{},
+	}
+
+	stuck := map[uuid.UUID]bool{}
+	w.Events.Subscribe(func(r events.Record) {
+		switch e := r.Event.(type) {
+		case events.AgentStuck:
+			res.StuckEvents++
+			stuck[e.AgentID] = true
+		case events.PathFailed:
+			res.PathFailures++
+		case events.GoodsProduced:
+			res.Production[e.Good] += e.Qty
+		}
+	})
+
+	res.Ticks = int(cfg.Days * float64(w.Calendar.DayLength) / float64(cfg.Step))
+	for range res.Ticks {
+		w.Tick(cfg.Step)
+		res.PathRequests += w.LastTickStats().PathRequests
+	}
+
+	res.Population = len(w.Agents)
+	res.MoneySupply = w.MoneySupply()
+	res.MoneyDrift = res.MoneySupply - w.Ledger.Supply()
+	res.Treasury = w.Castle.Treasury
+	for g, p := range w.AveragePrices() {
+		res.Prices[string(g)] = p
+	}
+	res.StuckAgents = len(stuck)
+	res.WallTime = time.Since(start)
+	return res
+}
@@ -0,0 +1,100 @@
+package batch
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"veatla/simulator/src/scenario"
+)
+
+func TestWriteCSV(t *testing.T) {
+	results := []Result{
+		{Seed: 1, Days: 0.5, Ticks: 10, Production: map[string]int{"wood": 3}, Prices: map[string]float64{"bread": 2}},
+		{Seed: 2, Days: 0.5, Ticks: 10, Production: map[string]int{"stone": 1}, WallTime: 1500 * time.Millisecond},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("%d rows, want a header and 2", len(rows))
+	}
+	// Goods missing from a seed still get a column, in sorted order.
+	header := rows[0]
+	if got := header[len(header)-3:]; !slices.Equal(got, []string{"production_stone", "production_wood", "price_bread"}) {
+		t.Fatalf("goods columns = %v", got)
+	}
+	col := func(name string) int { return slices.Index(header, name) }
+	for _, tc := range []struct {
+		row       int
+		name, val string
+	}{
+		{1, "seed", "1"},
+		{1, "production_wood", "3"},
+		{1, "production_stone", "0"},
+		{1, "price_bread", "2.0000"},
+		{2, "seed", "2"},
+		{2, "price_bread", "0.0000"},
+		{2, "wall_time_ms", "1500"},
+	} {
+		if got := rows[tc.row][col(tc.name)]; got != tc.val {
+			t.Errorf("row %d %s = %q, want %q", tc.row, tc.name, got, tc.val)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	results := []Result{{Seed: 7, Ticks: 3, Production: map[string]int{"wood": 2}, Prices: map[string]float64{"bread": 1.5}}}
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	var got []Result
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, results) {
+		t.Fatalf("round trip = %+v, want %+v", got, results)
+	}
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	cfg := Config{
+		Seeds: []int64{3, 1, 2},
+		Days:  0.05,
+		Step:  50 * time.Millisecond,
+		Scenario: func(seed int64) scenario.Config {
+			c := scenario.Default(seed)
+			c.Agents = 10
+			c.DayLength = time.Minute
+			return c
+		},
+	}
+	cfg.Parallel = 1
+	serial := Run(cfg)
+	cfg.Parallel = 3
+	parallel := Run(cfg)
+
+	for i, r := range serial {
+		if r.Seed != cfg.Seeds[i] {
+			t.Errorf("result %d is seed %d, want %d", i, r.Seed, cfg.Seeds[i])
+		}
+		if r.Ticks != 60 || r.MoneyDrift != 0 {
+			t.Errorf("seed %d: %d ticks, drift %d", r.Seed, r.Ticks, r.MoneyDrift)
+		}
+		p := parallel[i]
+		r.WallTime, p.WallTime = 0, 0
+		if !reflect.DeepEqual(r, p) {
+			t.Errorf("seed %d differs between serial and parallel runs:\n%+v\n%+v", r.Seed, r, p)
+		}
+	}
+}
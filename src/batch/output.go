@@ -0,0 +1,90 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// WriteJSON writes results as an indented JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// WriteCSV writes one row per seed. Production and price maps are flattened
+// into production_<good> and price_<good> columns over the union of goods.
+func WriteCSV(w io.Writer, results []Result) error {
+	production := unionKeys(results, func(r Result) []string { return keys(r.Production) })
+	prices := unionKeys(results, func(r Result) []string { return keys(r.Prices) })
+
+	header := []string{
+		"seed", "days", "ticks", "population",
+		"stuck_agents", "stuck_events", "path_requests", "path_failures",
+		"money_supply", "money_drift", "treasury", "wall_time_ms",
+	}
+	for _, g := range production {
+		header = append(header, "production_"+g)
+	}
+	for _, g := range prices {
+		header = append(header, "price_"+g)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range results {
+		row := []string{
+			strconv.FormatInt(r.Seed, 10),
+			strconv.FormatFloat(r.Days, 'f', -1, 64),
+			strconv.Itoa(r.Ticks),
+			strconv.Itoa(r.Population),
+			strconv.Itoa(r.StuckAgents),
+			strconv.Itoa(r.StuckEvents),
+			strconv.Itoa(r.PathRequests),
+			strconv.Itoa(r.PathFailures),
+			strconv.FormatInt(r.MoneySupply, 10),
+			strconv.FormatInt(r.MoneyDrift, 10),
+			strconv.FormatInt(r.Treasury, 10),
+			strconv.FormatInt(r.WallTime.Milliseconds(), 10),
+		}
+		for _, g := range production {
+			row = append(row, strconv.Itoa(r.Production[g]))
+		}
+		for _, g := range prices {
+			row = append(row, strconv.FormatFloat(r.Prices[g], 'f', 4, 64))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+func unionKeys(results []Result, get func(Result) []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, r := range results {
+		for _, k := range get(r) {
+			if !seen[k] {
+				seen[k] = true
+				out = append(out, k)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,98 @@
+package calendar
+
+import (
+	"math"
+	"time"
+)
+
+// Season is a quarter of the year.
+type Season int
+
+const (
+	Spring Season = iota
+	Summer
+	Autumn
+	Winter
+)
+
+func (s Season) String() string {
+	switch s {
+	case Summer:
+		return "summer"
+	case Autumn:
+		return "autumn"
+	case Winter:
+		return "winter"
+	default:
+		return "spring"
+	}
+}
+
+// MarshalText lets seasons appear by name in JSON.
+func (s Season) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// DefaultDayLength is the simulated duration of one in-world day unless a
+// scenario chooses another.
+const DefaultDayLength = 24 * time.Minute
+
+// daylightShift is how many hours longer summer days are than spring and
+// autumn days, at each end; winter days are as much shorter.
+var daylightShift = [4]float64{0, 1, 0, -1}
+
+// twilight is how many hours the light takes to come up or go down.
+const twilight = 1.0
+
+// Calendar maps simulated time to in-world dates. Day 0 is the first day
+// of spring in year 0.
+type Calendar struct {
+	// DayLength is the simulated duration of one in-world day.
+	DayLength     time.Duration
+	DaysPerSeason int
+	// Dawn and Dusk are the hours at which an equinox day starts and ends.
+	Dawn, Dusk float64
+	// StartHour is the time of day the simulation starts at.
+	StartHour float64
+}
+
+// Default returns the calendar used unless a scenario chooses another.
+func Default() Calendar {
+	return Calendar{DayLength: DefaultDayLength, DaysPerSeason: 7, Dawn: 6, Dusk: 20, StartHour: 6}
+}
+
+// Time is a moment on the calendar.
+type Time struct {
+	// Days is the number of whole days since the start.
+	Days   int    `json:"days"`
+	Year   int    `json:"year"`
+	Season Season `json:"season"`
+	// Day is the day of the season, from 0.
+	Day int `json:"day"`
+	// Hour is the time of day in hours, in [0, 24).
+	Hour float64 `json:"hour"`
+	// Light is how bright it is outside, from 0 at night to 1 by day.
+	Light float64 `json:"light"`
+	Night bool    `json:"night"`
+}
+
+// At returns the calendar time after elapsed simulated time.
+func (c Calendar) At(elapsed time.Duration) Time {
+	if c.DayLength <= 0 || c.DaysPerSeason <= 0 {
+		return Time{Light: 1}
+	}
+	elapsed += time.Duration(c.StartHour / 24 * float64(c.DayLength))
+	days := int(elapsed / c.DayLength)
+	t := Time{
+		Days:   days,
+		Year:   days / (4 * c.DaysPerSeason),
+		Season: Season(days / c.DaysPerSeason % 4),
+		Day:    days % c.DaysPerSeason,
+		Hour:   24 * float64(elapsed%c.DayLength) / float64(c.DayLength),
+	}
+	shift := daylightShift[t.Season]
+	dawn, dusk := c.Dawn-shift, c.Dusk+shift
+	t.Light = math.Min(math.Max(math.Min(t.Hour-dawn, dusk-t.Hour)/twilight+0.5, 0), 1)
+	t.Night = t.Hour < dawn || t.Hour >= dusk
+	return t
+}
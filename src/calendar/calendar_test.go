@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+// at returns the time at hour of the given day, counting from the start of
+// day 0 rather than from the calendar's start hour.
+func at(c Calendar, day int, hour float64) Time {
+	h := float64(day)*24 + hour - c.StartHour
+	return c.At(time.Duration(h / 24 * float64(c.DayLength)))
+}
+
+func TestDates(t *testing.T) {
+	c := Default()
+	for _, tc := range []struct {
+		day    int
+		hour   float64
+		year   int
+		season Season
+		of     int
+	}{
+		{0, 6, 0, Spring, 0},
+		{1, 0, 0, Spring, 1},
+		{7, 12, 0, Summer, 0},
+		{20, 23, 0, Autumn, 6},
+		{27, 1, 0, Winter, 6},
+		{28, 0, 1, Spring, 0},
+	} {
+		got := at(c, tc.day, tc.hour)
+		if got.Days != tc.day || got.Year != tc.year || got.Season != tc.season || got.Day != tc.of {
+			t.Errorf("day %d: got %+v, want year %d, %s day %d", tc.day, got, tc.year, tc.season, tc.of)
+		}
+		if d := got.Hour - tc.hour; d > 1e-6 || d < -1e-6 {
+			t.Errorf("day %d: hour %v, want %v", tc.day, got.Hour, tc.hour)
+		}
+	}
+	if got := c.At(0); got.Hour != c.StartHour {
+		t.Errorf("starts at %v, want %v", got.Hour, c.StartHour)
+	}
+}
+
+func TestDaylight(t *testing.T) {
+	c := Default()
+	summer, winter := 7, 21
+	for _, tc := range []struct {
+		name  string
+		day   int
+		hour  float64
+		light float64
+		night bool
+	}{
+		{"noon", 0, 12, 1, false},
+		{"midnight", 0, 0, 0, true},
+		{"dawn", 0, 6, 0.5, false},
+		{"before spring dawn", 0, 5.5, 0, true},
+		{"before summer dawn", summer, 5.5, 1, false},
+		{"winter evening", winter, 19.5, 0, true},
+		{"spring evening", 0, 19, 1, false},
+	} {
+		got := at(c, tc.day, tc.hour)
+		if d := got.Light - tc.light; d > 1e-6 || d < -1e-6 || got.Night != tc.night {
+			t.Errorf("%s: light %v night %v, want %v %v", tc.name, got.Light, got.Night, tc.light, tc.night)
+		}
+	}
+}
+
+func TestNoCalendar(t *testing.T) {
+	if got := (Calendar{}).At(time.Hour); got != (Time{Light: 1}) {
+		t.Fatalf("zero calendar gave %+v", got)
+	}
+}
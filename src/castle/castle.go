@@ -0,0 +1,142 @@
+package castle
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"veatla/simulator/src/goods"
+)
+
+// Faction is the faction of the lord's household and soldiers; the castle
+// gate lets it through when closed.
+const Faction = "castle"
+
+// Report is the castle's income and spending over one fiscal period.
+// Tithe is collected in kind, in units per good.
+type Report struct {
+	Period        int            `json:"period"`
+	FromTick      int            `json:"fromTick"`
+	ToTick        int            `json:"toTick"`
+	Policy        Policy         `json:"policy"`
+	IncomeTax     int64          `json:"incomeTax"`
+	SalesTax      int64          `json:"salesTax"`
+	Tolls         int64          `json:"tolls"`
+	Rent          int64          `json:"rent"`
+	Minted        int64          `json:"minted"`
+	Wages         int64          `json:"wages"`
+	Tithe         map[string]int `json:"tithe"`
+	TreasuryStart int64          `json:"treasuryStart"`
+	TreasuryEnd   int64          `json:"treasuryEnd"`
+}
+
+// Income is the coins the treasury took in during the period.
+func (r Report) Income() int64 {
+	return r.IncomeTax + r.SalesTax + r.Tolls + r.Rent + r.Minted
+}
+
+// Castle is the lord's household: it owns the treasury and the tithe
+// stores and sets the tax policy.
+type Castle struct {
+	Lord     string
+	Policy   Policy
+	Treasury int64
+	// Stores hold goods collected as tithe.
+	Stores goods.Stock
+	// Current accumulates the open fiscal period.
+	Current Report
+	// Archive keeps closed reports for the API.
+	Archive *Archive
+}
+
+// New creates a castle with the default policy and an archive of n reports.
+func New(lord string, n int) *Castle {
+	c := &Castle{
+		Lord:    lord,
+		Policy:  DefaultPolicy(),
+		Stores:  goods.Stock{},
+		Archive: NewArchive(n),
+	}
+	c.Current = Report{Policy: c.Policy, Tithe: map[string]int{}}
+	c.Archive.SetPolicy(c.Policy)
+	return c
+}
+
+// SetPolicy applies u and publishes the new policy to the archive.
+func (c *Castle) SetPolicy(u PolicyUpdate) error {
+	p, err := c.Policy.With(u)
+	if err != nil {
+		return err
+	}
+	c.Policy = p
+	c.Archive.SetPolicy(p)
+	return nil
+}
+
+// Close ends the current period at tick, archives its report and opens the
+// next one.
+func (c *Castle) Close(tick int) Report {
+	r := c.Current
+	r.ToTick = tick
+	r.TreasuryEnd = c.Treasury
+	c.Archive.add(r)
+	c.Current = Report{
+		Period:        r.Period + 1,
+		FromTick:      tick + 1,
+		Policy:        c.Policy,
+		Tithe:         map[string]int{},
+		TreasuryStart: c.Treasury,
+	}
+	return r
+}
+
+// Archive keeps the last closed reports and the current policy. It is safe
+// for concurrent use so the HTTP API can read it while the simulation runs.
+type Archive struct {
+	mu      sync.Mutex
+	policy  Policy
+	reports []Report
+	limit   int
+}
+
+// NewArchive creates an archive that keeps the last n reports.
+func NewArchive(n int) *Archive {
+	return &Archive{limit: max(n, 1)}
+}
+
+func (a *Archive) SetPolicy(p Policy) {
+	a.mu.Lock()
+	a.policy = p
+	a.mu.Unlock()
+}
+
+func (a *Archive) add(r Report) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.reports) == a.limit {
+		a.reports = append(a.reports[:0], a.reports[1:]...)
+	}
+	a.reports = append(a.reports, r)
+}
+
+// Reports returns the archived reports, oldest first.
+func (a *Archive) Reports() []Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Report(nil), a.reports...)
+}
+
+// ServeHTTP writes the current policy and the archived reports as JSON.
+func (a *Archive) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	a.mu.Lock()
+	body := struct {
+		Policy  Policy   `json:"policy"`
+		Reports []Report `json:"reports"`
+	}{a.policy, append([]Report{}, a.reports...)}
+	a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
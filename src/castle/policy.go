@@ -0,0 +1,75 @@
+package castle
+
+import "fmt"
+
+// Policy is the lord's tax policy. Rates are fractions in [0, 1]; Toll is
+// coins charged each time an outsider enters through the gate. Agents who
+// live or work inside the castle pass free.
+type Policy struct {
+	IncomeTax float64 `json:"incomeTax"`
+	SalesTax  float64 `json:"salesTax"`
+	Tithe     float64 `json:"tithe"`
+	Toll      int64   `json:"toll"`
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		IncomeTax: 0.1,
+		SalesTax:  0.05,
+		Tithe:     0.1,
+		Toll:      1,
+	}
+}
+
+// Burden is the combined rate agents feel when judging the lord.
+func (p Policy) Burden() float64 {
+	return p.IncomeTax + p.SalesTax + p.Tithe
+}
+
+func (p Policy) Validate() error {
+	for _, r := range []struct {
+		name string
+		v    float64
+	}{{"incomeTax", p.IncomeTax}, {"salesTax", p.SalesTax}, {"tithe", p.Tithe}} {
+		if r.v < 0 || r.v > 1 {
+			return fmt.Errorf("%s %.2f out of range [0, 1]", r.name, r.v)
+		}
+	}
+	if p.Toll < 0 {
+		return fmt.Errorf("toll %d must not be negative", p.Toll)
+	}
+	return nil
+}
+
+// PolicyUpdate changes only the fields that are set. It is the payload of
+// the "policy" server command.
+type PolicyUpdate struct {
+	IncomeTax *float64 `json:"incomeTax,omitempty"`
+	SalesTax  *float64 `json:"salesTax,omitempty"`
+	Tithe     *float64 `json:"tithe,omitempty"`
+	Toll      *int64   `json:"toll,omitempty"`
+}
+
+// With returns p with u applied, or an error if the result is invalid.
+func (p Policy) With(u PolicyUpdate) (Policy, error) {
+	if u.IncomeTax != nil {
+		p.IncomeTax = *u.IncomeTax
+	}
+	if u.SalesTax != nil {
+		p.SalesTax = *u.SalesTax
+	}
+	if u.Tithe != nil {
+		p.Tithe = *u.Tithe
+	}
+	if u.Toll != nil {
+		p.Toll = *u.Toll
+	}
+	return p, p.Validate()
+}
+
+// Tax returns the share of amount owed at rate, rounded to whole units. It
+// suits the tithe, which is taken in kind; coin taxes on small payments use
+// the world's levy, which carries fractions of a coin over.
+func Tax(amount int64, rate float64) int64 {
+	return int64(float64(amount)*rate + 0.5)
+}
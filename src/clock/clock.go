@@ -0,0 +1,224 @@
+package clock
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	MinSpeed = 0.5
+	MaxSpeed = 100.0
+
+	// defaultMaxCatchUp bounds how many ticks a single Advance may return per
+	// unit of speed, so a slow tick cannot snowball into an ever-growing
+	// backlog.
+	defaultMaxCatchUp = 20
+)
+
+// Clock drives a fixed-timestep simulation. Every tick advances simulated
+// time by the same Step; how many ticks run per wall-clock frame depends on
+// the speed multiplier, pause state and max-speed mode.
+type Clock struct {
+	mu          sync.Mutex
+	step        time.Duration
+	speed       float64
+	paused      bool
+	maxSpeed    bool
+	pending     int
+	maxCatchUp  int
+	accumulator time.Duration
+	lastWall    time.Time
+	ticks       uint64
+	lag         time.Duration
+	dropped     time.Duration
+	// covered is the wall time the last batch of ticks stands for at the
+	// current speed, and ran how long the caller reported running it.
+	covered time.Duration
+	ran     time.Duration
+}
+
+// Control is a clock command received from a client.
+type Control struct {
+	Action   string  `json:"action"`
+	Steps    int     `json:"steps,omitempty"`
+	Speed    float64 `json:"speed,omitempty"`
+	MaxSpeed bool    `json:"maxSpeed,omitempty"`
+}
+
+// New creates a running clock at x1 speed with the given simulated step.
+func New(step time.Duration) *Clock {
+	return &Clock{
+		step:       step,
+		speed:      1,
+		maxCatchUp: defaultMaxCatchUp,
+	}
+}
+
+// Step returns the simulated duration of one tick.
+func (c *Clock) Step() time.Duration {
+	return c.step
+}
+
+func (c *Clock) Pause() {
+	c.mu.Lock()
+	c.paused = true
+	c.accumulator = 0
+	c.mu.Unlock()
+}
+
+func (c *Clock) Resume() {
+	c.mu.Lock()
+	c.paused = false
+	c.mu.Unlock()
+}
+
+func (c *Clock) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+// StepN queues n ticks to run on the next Advance, even while paused.
+func (c *Clock) StepN(n int) {
+	if n <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.pending += n
+	c.mu.Unlock()
+}
+
+// SetSpeed sets the simulated-to-wall time ratio.
+func (c *Clock) SetSpeed(x float64) error {
+	if x < MinSpeed || x > MaxSpeed {
+		return fmt.Errorf("speed %.2f out of range [%.1f, %.0f]", x, MinSpeed, MaxSpeed)
+	}
+	c.mu.Lock()
+	c.speed = x
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Clock) Speed() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.speed
+}
+
+// SetMaxSpeed switches headless mode on or off. In max-speed mode the clock
+// ignores wall time and always hands out a full batch of ticks.
+func (c *Clock) SetMaxSpeed(on bool) {
+	c.mu.Lock()
+	c.maxSpeed = on
+	c.accumulator = 0
+	c.mu.Unlock()
+}
+
+func (c *Clock) MaxSpeed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxSpeed
+}
+
+// Apply executes a client control command.
+func (c *Clock) Apply(ctrl Control) error {
+	switch ctrl.Action {
+	case "pause":
+		c.Pause()
+	case "resume":
+		c.Resume()
+	case "step":
+		c.StepN(max(ctrl.Steps, 1))
+	case "speed":
+		return c.SetSpeed(ctrl.Speed)
+	case "maxSpeed":
+		c.SetMaxSpeed(ctrl.MaxSpeed)
+	default:
+		return fmt.Errorf("unknown clock action %q", ctrl.Action)
+	}
+	return nil
+}
+
+// Ran records how long the caller took to run the ticks the last Advance
+// handed out. Advance only drops ticks once running them falls behind.
+func (c *Clock) Ran(d time.Duration) {
+	c.mu.Lock()
+	c.ran = d
+	c.mu.Unlock()
+}
+
+// Advance reports how many ticks are due at wall time now and counts them as
+// run. At most the catch-up budget, which grows with speed, is handed out at
+// once. If the last batch took longer to run than the time it covered, the
+// excess is dropped and reported by Lag; otherwise it waits for the next
+// Advance.
+func (c *Clock) Advance(now time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elapsed := time.Duration(0)
+	if !c.lastWall.IsZero() {
+		elapsed = now.Sub(c.lastWall)
+	}
+	c.lastWall = now
+	c.lag = 0
+
+	n := c.pending
+	c.pending = 0
+
+	switch {
+	case c.paused:
+	case c.maxSpeed:
+		n += c.maxCatchUp
+	default:
+		c.accumulator += time.Duration(float64(elapsed) * c.speed)
+		due := int(c.accumulator / c.step)
+		budget := c.maxCatchUp * int(math.Ceil(c.speed))
+		if due > budget {
+			if c.ran > c.covered {
+				c.lag = time.Duration(due-budget) * c.step
+				c.dropped += c.lag
+				c.accumulator -= c.lag
+			}
+			due = budget
+		}
+		c.accumulator -= time.Duration(due) * c.step
+		c.covered = time.Duration(float64(time.Duration(due)*c.step) / c.speed)
+		n += due
+	}
+	c.ran = 0
+
+	c.ticks += uint64(n)
+	return n
+}
+
+// Ticks returns the number of ticks handed out so far.
+func (c *Clock) Ticks() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ticks
+}
+
+// SimTime returns the total simulated time.
+func (c *Clock) SimTime() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Duration(c.ticks) * c.step
+}
+
+// Lag returns the simulated time dropped by the last Advance because the
+// simulation could not keep up with real time. Zero means on schedule.
+func (c *Clock) Lag() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lag
+}
+
+// Dropped returns the total simulated time dropped since the clock started.
+func (c *Clock) Dropped() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
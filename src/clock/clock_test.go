@@ -0,0 +1,161 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+const step = 50 * time.Millisecond
+
+// frames advances c by n frames of d wall time each, reporting that every
+// batch took work to run, and returns the ticks handed out.
+func frames(c *Clock, start time.Time, n int, d, work time.Duration) (int, time.Time) {
+	total := 0
+	now := start
+	for range n {
+		now = now.Add(d)
+		total += c.Advance(now)
+		c.Ran(work)
+	}
+	return total, now
+}
+
+func TestAdvance(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		speed float64
+		frame time.Duration
+		want  int
+	}{
+		{"real time", 1, step, 10},
+		{"half speed", 0.5, step, 5},
+		{"double speed", 2, step, 20},
+		{"max speed", MaxSpeed, step, 1000},
+		{"long frames", MaxSpeed, time.Second, 20000},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(step)
+			if err := c.SetSpeed(tc.speed); err != nil {
+				t.Fatal(err)
+			}
+			start := time.Unix(0, 0)
+			c.Advance(start)
+			got, _ := frames(c, start, 10, tc.frame, 0)
+			if got != tc.want {
+				t.Errorf("ticks = %d, want %d", got, tc.want)
+			}
+			if c.Dropped() != 0 {
+				t.Errorf("idle clock dropped %v", c.Dropped())
+			}
+			if c.SimTime() != time.Duration(got)*step {
+				t.Errorf("SimTime = %v, want %v", c.SimTime(), time.Duration(got)*step)
+			}
+		})
+	}
+}
+
+func TestAdvanceCarriesBacklog(t *testing.T) {
+	c := New(step)
+	start := time.Unix(0, 0)
+	c.Advance(start)
+	// A stall that is not the simulation's fault is caught up over the next
+	// frames rather than dropped.
+	if n := c.Advance(start.Add(2 * time.Second)); n != defaultMaxCatchUp {
+		t.Fatalf("first frame after stall = %d, want %d", n, defaultMaxCatchUp)
+	}
+	c.Ran(0)
+	got, _ := frames(c, start.Add(2*time.Second), 2, step, 0)
+	if got != 2+40-defaultMaxCatchUp {
+		t.Errorf("backlog = %d, want %d", got, 2+40-defaultMaxCatchUp)
+	}
+	if c.Dropped() != 0 {
+		t.Errorf("dropped %v without overrunning", c.Dropped())
+	}
+}
+
+func TestAdvanceDropsWhenOverrunning(t *testing.T) {
+	c := New(step)
+	start := time.Unix(0, 0)
+	c.Advance(start)
+	// Every tick takes twice as long to run as it covers.
+	now := start
+	for range 5 {
+		now = now.Add(2 * time.Second)
+		n := c.Advance(now)
+		c.Ran(time.Duration(n) * 2 * step)
+	}
+	if c.Lag() == 0 || c.Dropped() == 0 {
+		t.Fatal("overrunning clock reported no lag")
+	}
+	if n := c.Advance(now.Add(time.Second)); n > defaultMaxCatchUp {
+		t.Errorf("handed out %d ticks, budget is %d", n, defaultMaxCatchUp)
+	}
+}
+
+func TestPauseAndStepN(t *testing.T) {
+	c := New(step)
+	start := time.Unix(0, 0)
+	c.Advance(start)
+	c.Pause()
+	if n := c.Advance(start.Add(time.Second)); n != 0 {
+		t.Fatalf("paused clock handed out %d ticks", n)
+	}
+	c.StepN(3)
+	c.StepN(0)
+	c.StepN(-1)
+	if n := c.Advance(start.Add(2 * time.Second)); n != 3 {
+		t.Fatalf("StepN(3) while paused handed out %d ticks", n)
+	}
+	if n := c.Advance(start.Add(3 * time.Second)); n != 0 {
+		t.Fatalf("steps ran twice: %d", n)
+	}
+	c.Resume()
+	if n := c.Advance(start.Add(3*time.Second + step)); n != 1 {
+		t.Fatalf("resumed clock handed out %d ticks, want 1", n)
+	}
+	if c.Ticks() != 4 {
+		t.Errorf("Ticks = %d, want 4", c.Ticks())
+	}
+}
+
+func TestSetSpeed(t *testing.T) {
+	c := New(step)
+	for _, x := range []float64{MinSpeed, 1, 7.5, MaxSpeed} {
+		if err := c.SetSpeed(x); err != nil {
+			t.Errorf("SetSpeed(%v): %v", x, err)
+		} else if c.Speed() != x {
+			t.Errorf("Speed = %v after SetSpeed(%v)", c.Speed(), x)
+		}
+	}
+	for _, x := range []float64{0, MinSpeed - 0.1, MaxSpeed + 1, -1} {
+		if err := c.SetSpeed(x); err == nil {
+			t.Errorf("SetSpeed(%v) accepted", x)
+		}
+	}
+	if c.Speed() != MaxSpeed {
+		t.Errorf("rejected speed changed Speed to %v", c.Speed())
+	}
+}
+
+func TestApply(t *testing.T) {
+	c := New(step)
+	for _, ctrl := range []Control{
+		{Action: "pause"},
+		{Action: "step"},
+		{Action: "speed", Speed: 4},
+		{Action: "maxSpeed", MaxSpeed: true},
+	} {
+		if err := c.Apply(ctrl); err != nil {
+			t.Fatalf("Apply(%+v): %v", ctrl, err)
+		}
+	}
+	if !c.Paused() || c.Speed() != 4 || !c.MaxSpeed() {
+		t.Fatal("controls not applied")
+	}
+	if n := c.Advance(time.Unix(0, 0)); n != 1 {
+		t.Errorf("paused step handed out %d ticks, want 1", n)
+	}
+	if err := c.Apply(Control{Action: "rewind"}); err == nil {
+		t.Error("unknown action accepted")
+	}
+}
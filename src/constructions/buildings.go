@@ -0,0 +1,41 @@
+package constructions
+
+import (
+	"veatla/simulator/src/goods"
+
+	"github.com/google/uuid"
+)
+
+// BuildingKind identifies what a building is used for.
+type BuildingKind string
+
+const (
+	BuildingHouse     BuildingKind = "house"
+	BuildingStockpile BuildingKind = "stockpile"
+	BuildingMarket    BuildingKind = "market"
+	BuildingMint      BuildingKind = "mint"
+	BuildingTavern    BuildingKind = "tavern"
+)
+
+// Building is a blocking footprint with a purpose.
+type Building struct {
+	Obstacle
+	Kind BuildingKind
+	// Capacity is how many residents (houses) or workers the building holds.
+	Capacity int
+	// Stock holds goods stored in the building.
+	Stock goods.Stock
+	// Money is the coins the building owns.
+	Money int64
+	// Workers are the agents employed here, at most Capacity of them.
+	Workers []uuid.UUID
+}
+
+func CreateBuilding(id uuid.UUID, kind BuildingKind, MinX, MinZ, MaxX, MaxZ float64, capacity int) Building {
+	return Building{
+		Obstacle: CreateObstacle(id, MinX, MinZ, MaxX, MaxZ),
+		Kind:     kind,
+		Capacity: capacity,
+		Stock:    goods.Stock{},
+	}
+}
@@ -0,0 +1,49 @@
+package constructions
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// FortKind identifies a piece of the castle's fortifications.
+type FortKind string
+
+const (
+	FortWall  FortKind = "wall"
+	FortGate  FortKind = "gate"
+	FortTower FortKind = "tower"
+)
+
+// Fortification is a wall segment, tower or gate. Walls and towers always
+// block movement; a gate blocks only while closed, and even then lets its
+// Factions through.
+type Fortification struct {
+	Obstacle
+	Kind FortKind
+	// Open is whether a gate is open. It is ignored for walls and towers.
+	Open bool
+	// Factions may pass a closed gate.
+	Factions []string
+}
+
+func CreateFortification(id uuid.UUID, kind FortKind, MinX, MinZ, MaxX, MaxZ float64) Fortification {
+	return Fortification{
+		Obstacle: CreateObstacle(id, MinX, MinZ, MaxX, MaxZ),
+		Kind:     kind,
+		Open:     kind == FortGate,
+	}
+}
+
+// Blocks reports whether the fortification stops a member of faction.
+func (f *Fortification) Blocks(faction string) bool {
+	if f.Kind != FortGate {
+		return true
+	}
+	return !f.Open && !f.Admits(faction)
+}
+
+// Admits reports whether faction may pass the gate while it is closed.
+func (f *Fortification) Admits(faction string) bool {
+	return faction != "" && slices.Contains(f.Factions, faction)
+}
@@ -8,12 +8,19 @@ type Obstacle struct {
 	MaxX, MaxZ float64
 }
 
-func CreateObstacle(MinX, MinZ, MaxX, MaxZ float64) Obstacle {
+// CreateObstacle creates an obstacle with the given ID. IDs come from the
+// world's RNG so seeded runs are reproducible.
+func CreateObstacle(id uuid.UUID, MinX, MinZ, MaxX, MaxZ float64) Obstacle {
 	return Obstacle{
-		ID:   uuid.New(),
+		ID:   id,
 		MinX: MinX,
 		MinZ: MinZ,
 		MaxX: MaxX,
 		MaxZ: MaxZ,
 	}
 }
+
+// Contains reports whether (x, z) lies within the footprint.
+func (o *Obstacle) Contains(x, z float64) bool {
+	return x >= o.MinX && x < o.MaxX && z >= o.MinZ && z < o.MaxZ
+}
@@ -0,0 +1,116 @@
+package constructions
+
+import (
+	"fmt"
+
+	"veatla/simulator/src/goods"
+
+	"github.com/google/uuid"
+)
+
+// scaffoldAt is the progress from which a site blocks movement.
+const scaffoldAt = 0.5
+
+// Blueprint is what it takes to put up one kind of building.
+type Blueprint struct {
+	Kind         BuildingKind
+	Width, Depth float64
+	Capacity     int
+	Materials    goods.Stock
+	// Work is the builder-days of labour needed once materials are in.
+	Work float64
+}
+
+// Blueprints are the buildings that can be placed as construction sites.
+var Blueprints = map[BuildingKind]Blueprint{
+	BuildingHouse: {
+		Kind: BuildingHouse, Width: 3, Depth: 3, Capacity: 4,
+		Materials: goods.Stock{goods.Wood: 12, goods.Stone: 4},
+		Work:      0.25,
+	},
+	BuildingStockpile: {
+		Kind: BuildingStockpile, Width: 3, Depth: 3, Capacity: 2,
+		Materials: goods.Stock{goods.Wood: 8},
+		Work:      0.1,
+	},
+	BuildingMarket: {
+		Kind: BuildingMarket, Width: 3, Depth: 3, Capacity: 2,
+		Materials: goods.Stock{goods.Wood: 10, goods.Stone: 6},
+		Work:      0.3,
+	},
+	BuildingMint: {
+		Kind: BuildingMint, Width: 3, Depth: 3, Capacity: 2,
+		Materials: goods.Stock{goods.Stone: 12, goods.Iron: 2},
+		Work:      0.5,
+	},
+	BuildingTavern: {
+		Kind: BuildingTavern, Width: 3, Depth: 3, Capacity: 2,
+		Materials: goods.Stock{goods.Wood: 10, goods.Stone: 2},
+		Work:      0.2,
+	},
+}
+
+// Site is a building under construction. It does not block movement until
+// it is scaffolded, and becomes a Building once complete.
+type Site struct {
+	Obstacle
+	Blueprint Blueprint
+	// Delivered is the materials brought to the site so far.
+	Delivered goods.Stock
+	// Progress is the fraction of Blueprint.Work done, in [0, 1].
+	Progress float64
+	// Priority orders sites for builders; higher goes first.
+	Priority int
+	// Scaffolded is set once the site blocks movement.
+	Scaffolded bool
+}
+
+// CreateSite lays out a site for kind with its minimum corner at (x, z).
+func CreateSite(id uuid.UUID, kind BuildingKind, x, z float64) (Site, error) {
+	bp, ok := Blueprints[kind]
+	if !ok {
+		return Site{}, fmt.Errorf("no blueprint for %q", kind)
+	}
+	return Site{
+		Obstacle:  CreateObstacle(id, x, z, x+bp.Width, z+bp.Depth),
+		Blueprint: bp,
+		Delivered: goods.Stock{},
+	}, nil
+}
+
+// Missing returns the first material still needed, in stable order.
+func (s *Site) Missing() (goods.Good, int, bool) {
+	for _, g := range s.Blueprint.Materials.Goods() {
+		if n := s.Blueprint.Materials[g] - s.Delivered[g]; n > 0 {
+			return g, n, true
+		}
+	}
+	return "", 0, false
+}
+
+// Needs returns how many more units of g the site requires.
+func (s *Site) Needs(g goods.Good) int {
+	return max(s.Blueprint.Materials[g]-s.Delivered[g], 0)
+}
+
+// Supplied reports whether every material has been delivered.
+func (s *Site) Supplied() bool {
+	_, _, missing := s.Missing()
+	return !missing
+}
+
+// Complete reports whether all work is done.
+func (s *Site) Complete() bool {
+	return s.Progress >= 1
+}
+
+// ShouldScaffold reports whether the site has progressed far enough to
+// block movement.
+func (s *Site) ShouldScaffold() bool {
+	return !s.Scaffolded && s.Progress >= scaffoldAt
+}
+
+// Building returns the finished building, keeping the site's ID.
+func (s *Site) Building() Building {
+	return CreateBuilding(s.ID, s.Blueprint.Kind, s.MinX, s.MinZ, s.MaxX, s.MaxZ, s.Blueprint.Capacity)
+}
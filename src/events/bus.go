@@ -0,0 +1,94 @@
+package events
+
+import "sync"
+
+// Record is an event stamped with the tick it was published on.
+type Record struct {
+	Tick  int   `json:"tick"`
+	Event Event `json:"event"`
+}
+
+// Handler receives published events. Handlers run synchronously on the
+// publisher's goroutine and must not publish themselves.
+type Handler func(r Record)
+
+// Bus fans events out to subscribers and keeps a bounded history.
+// It is safe for concurrent use.
+type Bus struct {
+	mu      sync.Mutex
+	tick    int
+	subs    map[int]Handler
+	order   []int
+	nextSub int
+	history []Record
+	next    int
+	full    bool
+}
+
+// NewBus creates a bus that remembers the last historySize events.
+func NewBus(historySize int) *Bus {
+	return &Bus{
+		subs:    make(map[int]Handler),
+		history: make([]Record, max(historySize, 1)),
+	}
+}
+
+// SetTick sets the tick stamped on subsequently published events.
+func (b *Bus) SetTick(tick int) {
+	b.mu.Lock()
+	b.tick = tick
+	b.mu.Unlock()
+}
+
+// Subscribe registers h and returns a function that removes it.
+func (b *Bus) Subscribe(h Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextSub
+	b.nextSub++
+	b.subs[id] = h
+	b.order = append(b.order, id)
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, id)
+		for i, sid := range b.order {
+			if sid == id {
+				b.order = append(b.order[:i], b.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Publish records e and delivers it to every subscriber in subscription order.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r := Record{Tick: b.tick, Event: e}
+	b.history[b.next] = r
+	b.next = (b.next + 1) % len(b.history)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for _, id := range b.order {
+		b.subs[id](r)
+	}
+}
+
+// History returns the remembered events, oldest first.
+func (b *Bus) History() []Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]Record(nil), b.history[:b.next]...)
+	}
+	out := make([]Record, 0, len(b.history))
+	out = append(out, b.history[b.next:]...)
+	return append(out, b.history[:b.next]...)
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func stuck(n int) AgentStuck { return AgentStuck{Ticks: n} }
+
+func TestHistoryIsBounded(t *testing.T) {
+	b := NewBus(3)
+	for i := range 5 {
+		b.SetTick(i)
+		b.Publish(stuck(i))
+	}
+	var ticks []int
+	for _, r := range b.History() {
+		if r.Event.(AgentStuck).Ticks != r.Tick {
+			t.Errorf("event %v stamped with tick %d", r.Event, r.Tick)
+		}
+		ticks = append(ticks, r.Tick)
+	}
+	if !slices.Equal(ticks, []int{2, 3, 4}) {
+		t.Fatalf("history ticks = %v, want [2 3 4]", ticks)
+	}
+}
+
+func TestSubscribers(t *testing.T) {
+	b := NewBus(8)
+	var got []string
+	first := b.Subscribe(func(r Record) { got = append(got, "first") })
+	b.Subscribe(func(r Record) {
+		if r.Event.Kind() != KindAgentSpawned {
+			t.Errorf("kind = %s, want %s", r.Event.Kind(), KindAgentSpawned)
+		}
+		got = append(got, "second")
+	})
+
+	b.Publish(AgentSpawned{AgentID: uuid.UUID{15: 1}})
+	first()
+	first()
+	b.Publish(AgentSpawned{AgentID: uuid.UUID{15: 2}})
+
+	if want := []string{"first", "second", "second"}; !slices.Equal(got, want) {
+		t.Fatalf("deliveries = %v, want %v", got, want)
+	}
+}
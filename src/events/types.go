@@ -0,0 +1,318 @@
+package events
+
+import "github.com/google/uuid"
+
+// Kind identifies an event type on the wire and in metrics.
+type Kind string
+
+const (
+	KindAgentSpawned       Kind = "agentSpawned"
+	KindTargetChosen       Kind = "targetChosen"
+	KindPathFailed         Kind = "pathFailed"
+	KindAgentStuck         Kind = "agentStuck"
+	KindObstaclePlaced     Kind = "obstaclePlaced"
+	KindAgentDespawned     Kind = "agentDespawned"
+	KindBuildingPlaced     Kind = "buildingPlaced"
+	KindGoodsPickedUp      Kind = "goodsPickedUp"
+	KindGoodsDelivered     Kind = "goodsDelivered"
+	KindItemDropped        Kind = "itemDropped"
+	KindTradeExecuted      Kind = "tradeExecuted"
+	KindGoodsProduced      Kind = "goodsProduced"
+	KindPolicyChanged      Kind = "policyChanged"
+	KindFiscalPeriodClosed Kind = "fiscalPeriodClosed"
+	KindSitePlaced         Kind = "sitePlaced"
+	KindSiteCancelled      Kind = "siteCancelled"
+	KindSiteCompleted      Kind = "siteCompleted"
+	KindGateToggled        Kind = "gateToggled"
+	KindZoneDesignated     Kind = "zoneDesignated"
+	KindZoneRemoved        Kind = "zoneRemoved"
+	KindFieldWorked        Kind = "fieldWorked"
+	KindCropRotted         Kind = "cropRotted"
+	KindResourceGathered   Kind = "resourceGathered"
+	KindNodeDepleted       Kind = "nodeDepleted"
+	KindNodeRegrown        Kind = "nodeRegrown"
+	KindWeatherChanged     Kind = "weatherChanged"
+	KindActivityStarted    Kind = "activityStarted"
+	KindHouseholdFormed    Kind = "householdFormed"
+	KindHouseholdJoined    Kind = "householdJoined"
+	KindHouseholdDissolved Kind = "householdDissolved"
+)
+
+// Reasons attached to AgentSpawned and AgentDespawned.
+const (
+	ReasonInitial    = "initial"
+	ReasonImmigrated = "immigrated"
+	ReasonBorn       = "born"
+	ReasonDied       = "died"
+	ReasonEmigrated  = "emigrated"
+)
+
+// Event is anything published on the bus.
+type Event interface {
+	Kind() Kind
+}
+
+// AgentSpawned is published when an agent enters the world.
+type AgentSpawned struct {
+	AgentID uuid.UUID `json:"agentId"`
+	X       float64   `json:"x"`
+	Z       float64   `json:"z"`
+	Reason  string    `json:"reason"`
+}
+
+// AgentDespawned is published when an agent leaves the world for good.
+type AgentDespawned struct {
+	AgentID uuid.UUID `json:"agentId"`
+	X       float64   `json:"x"`
+	Z       float64   `json:"z"`
+	Reason  string    `json:"reason"`
+}
+
+// TargetChosen is published when an agent picks a new movement target.
+type TargetChosen struct {
+	AgentID uuid.UUID `json:"agentId"`
+	FromX   float64   `json:"fromX"`
+	FromZ   float64   `json:"fromZ"`
+	TargetX float64   `json:"targetX"`
+	TargetZ float64   `json:"targetZ"`
+}
+
+// PathFailed is published when A* cannot reach an agent's target.
+type PathFailed struct {
+	AgentID uuid.UUID `json:"agentId"`
+	FromX   float64   `json:"fromX"`
+	FromZ   float64   `json:"fromZ"`
+	TargetX float64   `json:"targetX"`
+	TargetZ float64   `json:"targetZ"`
+}
+
+// AgentStuck is published when an agent has not moved for its stuck threshold.
+type AgentStuck struct {
+	AgentID uuid.UUID `json:"agentId"`
+	X       float64   `json:"x"`
+	Z       float64   `json:"z"`
+	Ticks   int       `json:"ticks"`
+}
+
+// BuildingPlaced is published when a building is added to the world.
+type BuildingPlaced struct {
+	BuildingID   uuid.UUID `json:"buildingId"`
+	BuildingKind string    `json:"buildingKind"`
+	MinX         float64   `json:"minX"`
+	MinZ         float64   `json:"minZ"`
+	MaxX         float64   `json:"maxX"`
+	MaxZ         float64   `json:"maxZ"`
+}
+
+// ObstaclePlaced is published when an obstacle is added to the world.
+type ObstaclePlaced struct {
+	ObstacleID uuid.UUID `json:"obstacleId"`
+	MinX       float64   `json:"minX"`
+	MinZ       float64   `json:"minZ"`
+	MaxX       float64   `json:"maxX"`
+	MaxZ       float64   `json:"maxZ"`
+}
+
+// GoodsPickedUp is published when an agent takes goods from a building or
+// an item on the ground.
+type GoodsPickedUp struct {
+	AgentID  uuid.UUID `json:"agentId"`
+	SourceID uuid.UUID `json:"sourceId"`
+	Good     string    `json:"good"`
+	Qty      int       `json:"qty"`
+}
+
+// GoodsDelivered is published when an agent puts goods into a building.
+type GoodsDelivered struct {
+	AgentID    uuid.UUID `json:"agentId"`
+	BuildingID uuid.UUID `json:"buildingId"`
+	Good       string    `json:"good"`
+	Qty        int       `json:"qty"`
+}
+
+// ItemDropped is published when goods are left on the ground.
+type ItemDropped struct {
+	ItemID uuid.UUID `json:"itemId"`
+	Good   string    `json:"good"`
+	Qty    int       `json:"qty"`
+	X      float64   `json:"x"`
+	Z      float64   `json:"z"`
+}
+
+// TradeExecuted is published when a market order is filled and settled.
+type TradeExecuted struct {
+	MarketID uuid.UUID `json:"marketId"`
+	Good     string    `json:"good"`
+	Buyer    uuid.UUID `json:"buyer"`
+	Seller   uuid.UUID `json:"seller"`
+	Qty      int       `json:"qty"`
+	Price    int64     `json:"price"`
+}
+
+// GoodsProduced is published when a building produces goods. Tithe is the
+// part taken by the castle.
+type GoodsProduced struct {
+	BuildingID uuid.UUID `json:"buildingId"`
+	Good       string    `json:"good"`
+	Qty        int       `json:"qty"`
+	Tithe      int       `json:"tithe"`
+}
+
+// PolicyChanged is published when the lord changes the tax policy.
+type PolicyChanged struct {
+	IncomeTax float64 `json:"incomeTax"`
+	SalesTax  float64 `json:"salesTax"`
+	Tithe     float64 `json:"tithe"`
+	Toll      int64   `json:"toll"`
+}
+
+// FiscalPeriodClosed is published when the castle closes a fiscal report.
+type FiscalPeriodClosed struct {
+	Period   int   `json:"period"`
+	Income   int64 `json:"income"`
+	Wages    int64 `json:"wages"`
+	Treasury int64 `json:"treasury"`
+}
+
+// SitePlaced is published when a construction site is laid out.
+type SitePlaced struct {
+	SiteID       uuid.UUID `json:"siteId"`
+	BuildingKind string    `json:"buildingKind"`
+	MinX         float64   `json:"minX"`
+	MinZ         float64   `json:"minZ"`
+	MaxX         float64   `json:"maxX"`
+	MaxZ         float64   `json:"maxZ"`
+	Priority     int       `json:"priority"`
+}
+
+// SiteCancelled is published when a construction site is abandoned.
+type SiteCancelled struct {
+	SiteID uuid.UUID `json:"siteId"`
+}
+
+// SiteCompleted is published when a construction site becomes a building.
+// The building keeps the site's ID.
+type SiteCompleted struct {
+	SiteID       uuid.UUID `json:"siteId"`
+	BuildingKind string    `json:"buildingKind"`
+}
+
+// GateToggled is published when a gate is opened or closed.
+type GateToggled struct {
+	GateID uuid.UUID `json:"gateId"`
+	Open   bool      `json:"open"`
+}
+
+// ZoneDesignated is published when an area of the map is designated.
+type ZoneDesignated struct {
+	ZoneID   uuid.UUID `json:"zoneId"`
+	ZoneKind string    `json:"zoneKind"`
+	MinX     float64   `json:"minX"`
+	MinZ     float64   `json:"minZ"`
+	MaxX     float64   `json:"maxX"`
+	MaxZ     float64   `json:"maxZ"`
+}
+
+// ZoneRemoved is published when a designation is lifted.
+type ZoneRemoved struct {
+	ZoneID uuid.UUID `json:"zoneId"`
+}
+
+// FieldWorked is published when a farm field moves to a new stage.
+type FieldWorked struct {
+	ZoneID uuid.UUID `json:"zoneId"`
+	Stage  string    `json:"stage"`
+	// Qty is the harvest, left in the field for farmers to carry off.
+	Qty int `json:"qty,omitempty"`
+}
+
+// CropRotted is published when a crop fails or is left unharvested too long.
+type CropRotted struct {
+	ZoneID uuid.UUID `json:"zoneId"`
+	Crop   string    `json:"crop"`
+}
+
+// ResourceGathered is published when a gatherer leaves a node with a load.
+type ResourceGathered struct {
+	AgentID uuid.UUID `json:"agentId"`
+	NodeID  uuid.UUID `json:"nodeId"`
+	Good    string    `json:"good"`
+	Qty     int       `json:"qty"`
+}
+
+// NodeDepleted is published when a resource node is worked out and its
+// footprint cleared.
+type NodeDepleted struct {
+	NodeID   uuid.UUID `json:"nodeId"`
+	NodeKind string    `json:"nodeKind"`
+}
+
+// NodeRegrown is published when a depleted tree grows back.
+type NodeRegrown struct {
+	NodeID   uuid.UUID `json:"nodeId"`
+	NodeKind string    `json:"nodeKind"`
+}
+
+// WeatherChanged is published when the weather turns.
+type WeatherChanged struct {
+	Weather string `json:"weather"`
+}
+
+// ActivityStarted is published when an agent's routine moves it on to its
+// next activity.
+type ActivityStarted struct {
+	AgentID  uuid.UUID `json:"agentId"`
+	Activity string    `json:"activity"`
+}
+
+// HouseholdFormed is published when agents move into an empty house.
+type HouseholdFormed struct {
+	HouseholdID uuid.UUID `json:"householdId"`
+	HomeID      uuid.UUID `json:"homeId"`
+}
+
+// HouseholdJoined is published when an agent moves in with a household or
+// is born into one.
+type HouseholdJoined struct {
+	HouseholdID uuid.UUID `json:"householdId"`
+	AgentID     uuid.UUID `json:"agentId"`
+	HomeID      uuid.UUID `json:"homeId"`
+}
+
+// HouseholdDissolved is published when the last member of a household dies
+// or leaves.
+type HouseholdDissolved struct {
+	HouseholdID uuid.UUID `json:"householdId"`
+	HomeID      uuid.UUID `json:"homeId"`
+}
+
+func (AgentSpawned) Kind() Kind       { return KindAgentSpawned }
+func (TargetChosen) Kind() Kind       { return KindTargetChosen }
+func (PathFailed) Kind() Kind         { return KindPathFailed }
+func (AgentStuck) Kind() Kind         { return KindAgentStuck }
+func (ObstaclePlaced) Kind() Kind     { return KindObstaclePlaced }
+func (AgentDespawned) Kind() Kind     { return KindAgentDespawned }
+func (BuildingPlaced) Kind() Kind     { return KindBuildingPlaced }
+func (GoodsPickedUp) Kind() Kind      { return KindGoodsPickedUp }
+func (GoodsDelivered) Kind() Kind     { return KindGoodsDelivered }
+func (ItemDropped) Kind() Kind        { return KindItemDropped }
+func (TradeExecuted) Kind() Kind      { return KindTradeExecuted }
+func (GoodsProduced) Kind() Kind      { return KindGoodsProduced }
+func (PolicyChanged) Kind() Kind      { return KindPolicyChanged }
+func (FiscalPeriodClosed) Kind() Kind { return KindFiscalPeriodClosed }
+func (SitePlaced) Kind() Kind         { return KindSitePlaced }
+func (SiteCancelled) Kind() Kind      { return KindSiteCancelled }
+func (SiteCompleted) Kind() Kind      { return KindSiteCompleted }
+func (GateToggled) Kind() Kind        { return KindGateToggled }
+func (ZoneDesignated) Kind() Kind     { return KindZoneDesignated }
+func (ZoneRemoved) Kind() Kind        { return KindZoneRemoved }
+func (FieldWorked) Kind() Kind        { return KindFieldWorked }
+func (CropRotted) Kind() Kind         { return KindCropRotted }
+func (ResourceGathered) Kind() Kind   { return KindResourceGathered }
+func (NodeDepleted) Kind() Kind       { return KindNodeDepleted }
+func (NodeRegrown) Kind() Kind        { return KindNodeRegrown }
+func (WeatherChanged) Kind() Kind     { return KindWeatherChanged }
+func (ActivityStarted) Kind() Kind    { return KindActivityStarted }
+func (HouseholdFormed) Kind() Kind    { return KindHouseholdFormed }
+func (HouseholdJoined) Kind() Kind    { return KindHouseholdJoined }
+func (HouseholdDissolved) Kind() Kind { return KindHouseholdDissolved }
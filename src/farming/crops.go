@@ -0,0 +1,63 @@
+package farming
+
+import (
+	"slices"
+
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/goods"
+)
+
+// Crop describes how a crop grows.
+type Crop struct {
+	Name  string
+	Yield goods.Good
+	// PerTile is the harvest per tile of a healthy crop on fully fertile soil.
+	PerTile float64
+	// GrowDays is how long the crop takes to ripen at a growth rate of 1 on
+	// fully fertile soil.
+	GrowDays float64
+	// Rates is the growth rate in each season. A zero rate means the crop
+	// suffers, and dies if it stays there too long.
+	Rates [4]float64
+	// SowIn are the seasons the crop can be sown in.
+	SowIn []calendar.Season
+	// RotDays is how long a ripe crop keeps before it rots in the field.
+	RotDays float64
+}
+
+// CanSow reports whether the crop may be sown in season s.
+func (c Crop) CanSow(s calendar.Season) bool {
+	return slices.Contains(c.SowIn, s)
+}
+
+// DefaultCrop is sown on farm fields that do not name a crop.
+const DefaultCrop = "wheat"
+
+// Crops are the known crops by name.
+var Crops = map[string]Crop{
+	"wheat": {
+		Name: "wheat", Yield: goods.Grain, PerTile: 3, GrowDays: 8,
+		Rates: [4]float64{1, 1.2, 0.6, 0},
+		SowIn: []calendar.Season{calendar.Spring}, RotDays: 4,
+	},
+	"barley": {
+		Name: "barley", Yield: goods.Grain, PerTile: 2, GrowDays: 5,
+		Rates: [4]float64{1, 1, 0.8, 0},
+		SowIn: []calendar.Season{calendar.Spring, calendar.Summer}, RotDays: 3,
+	},
+	"rye": {
+		Name: "rye", Yield: goods.Grain, PerTile: 2, GrowDays: 10,
+		Rates: [4]float64{1, 0.8, 0.6, 0.3},
+		SowIn: []calendar.Season{calendar.Autumn, calendar.Winter, calendar.Spring}, RotDays: 5,
+	},
+}
+
+// Harvests reports whether g is the yield of some crop.
+func Harvests(g goods.Good) bool {
+	for _, c := range Crops {
+		if c.Yield == g {
+			return true
+		}
+	}
+	return false
+}
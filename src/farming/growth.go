@@ -0,0 +1,61 @@
+package farming
+
+import (
+	"math"
+
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/zones"
+)
+
+const (
+	// blightDays is how long a sown crop survives a season it cannot grow in.
+	blightDays = 2
+	// exhaustion is the fertility a harvest takes from the soil.
+	exhaustion = 0.1
+	// recovery is the fertility fallow soil regains per day, up to its Soil.
+	recovery = 0.05
+	// minFertility is the floor exhaustion stops at.
+	minFertility = 0.2
+)
+
+// Grow advances a farm field by days of simulated time in season s: sown
+// crops grow or wither, ripe crops wait to be harvested until they rot, and
+// fallow soil recovers. weather scales the growth rate. It reports whether
+// the field changed stage.
+func Grow(z *zones.Zone, c Crop, s calendar.Season, weather, days float64) bool {
+	switch z.Stage {
+	case zones.StageSown:
+		rate := c.Rates[s]
+		if rate == 0 {
+			z.Health -= days / blightDays
+			if z.Health <= 0 {
+				z.Health = 0
+				return z.Rot() == nil
+			}
+			return false
+		}
+		z.Growth += days * rate * weather * z.Fertility / c.GrowDays
+		if z.Growth >= 1 {
+			z.Growth = 1
+			return z.Ripen() == nil
+		}
+	case zones.StageRipe:
+		z.RipeDays += days
+		if z.RipeDays > c.RotDays {
+			return z.Rot() == nil
+		}
+	case zones.StageFallow:
+		z.Fertility = math.Min(z.Fertility+days*recovery, z.Soil)
+	}
+	return false
+}
+
+// Yield is what harvesting a ripe field brings in.
+func Yield(z *zones.Zone, c Crop) int {
+	return int(math.Round(z.Area() * c.PerTile * z.Fertility * z.Health))
+}
+
+// Exhaust takes a harvest's toll on the soil.
+func Exhaust(z *zones.Zone) {
+	z.Fertility = math.Max(z.Fertility-exhaustion, minFertility)
+}
@@ -0,0 +1,365 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/resources"
+
+	"github.com/google/uuid"
+)
+
+const (
+	cellSize = 1.0
+	// tileEpsilon keeps an obstacle's max edge inside its last tile so the
+	// spatial hash (which inserts max edges inclusively) does not spill into
+	// the neighbouring tile.
+	tileEpsilon = 1e-3
+
+	// treeChance is the share of forest tiles that get a tree.
+	treeChance = 0.15
+	// nodeSpacing is the minimum gap in tiles between two nodes, so that
+	// paths can always run between them.
+	nodeSpacing = 2
+
+	hillLevel     = 0.66
+	forestLevel   = 0.58
+	gateWidth     = 3
+	depositMargin = 6.0
+)
+
+type generator struct {
+	m         *Map
+	rng       *rand.Rand
+	elevation valueNoise
+	moisture  valueNoise
+}
+
+// Generate builds a castle region for the given seed. The same seed and size
+// always produce the same map, including obstacle IDs.
+func Generate(seed int64, width, height float64) Map {
+	cols := int(math.Ceil(width / cellSize))
+	rows := int(math.Ceil(height / cellSize))
+	m := Map{
+		Seed:      seed,
+		Width:     width,
+		Height:    height,
+		CellSize:  cellSize,
+		Cols:      cols,
+		Rows:      rows,
+		Tiles:     make([]Terrain, cols*rows),
+		Elevation: make([]float64, cols*rows),
+	}
+	g := &generator{
+		m:         &m,
+		rng:       rand.New(rand.NewSource(seed)),
+		elevation: newValueNoise(seed),
+		moisture:  newValueNoise(seed ^ 0x5DEECE66D),
+	}
+
+	g.terrain()
+	g.river()
+	g.castle()
+	g.deposits()
+	g.obstacles()
+	g.nodes()
+	return m
+}
+
+func (g *generator) index(cx, cz int) int {
+	return cz*g.m.Cols + cx
+}
+
+func (g *generator) inBounds(cx, cz int) bool {
+	return cx >= 0 && cz >= 0 && cx < g.m.Cols && cz < g.m.Rows
+}
+
+func (g *generator) newID() uuid.UUID {
+	id, err := uuid.NewRandomFromReader(g.rng)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// tileObstacle creates an obstacle covering tiles [x0..x1] x [z0..z1] inclusive.
+func (g *generator) tileObstacle(x0, z0, x1, z1 int) constructions.Obstacle {
+	cs := g.m.CellSize
+	return constructions.CreateObstacle(
+		g.newID(),
+		float64(x0)*cs, float64(z0)*cs,
+		float64(x1+1)*cs-tileEpsilon, float64(z1+1)*cs-tileEpsilon,
+	)
+}
+
+func (g *generator) terrain() {
+	for cz := 0; cz < g.m.Rows; cz++ {
+		for cx := 0; cx < g.m.Cols; cx++ {
+			x, z := float64(cx)/12, float64(cz)/12
+			e := g.elevation.FBM(x, z, 4)
+			moist := g.moisture.FBM(x*1.5, z*1.5, 3)
+
+			i := g.index(cx, cz)
+			g.m.Elevation[i] = e
+			switch {
+			case e > hillLevel:
+				g.m.Tiles[i] = TerrainHill
+			case moist > forestLevel:
+				g.m.Tiles[i] = TerrainForest
+			default:
+				g.m.Tiles[i] = TerrainGrass
+			}
+		}
+	}
+}
+
+// river carves a meandering river across the map along one axis and leaves
+// two fords so both banks stay reachable.
+func (g *generator) river() {
+	alongZ := g.rng.Intn(2) == 0
+	length, span := g.m.Rows, g.m.Cols
+	if !alongZ {
+		length, span = g.m.Cols, g.m.Rows
+	}
+	if length == 0 || span < 8 {
+		return
+	}
+
+	base := float64(span) * (0.2 + g.rng.Float64()*0.6)
+	width := 1 + g.rng.Intn(2)
+	phase := g.rng.Float64() * 100
+	fords := map[int]bool{}
+	for _, f := range []float64{0.3, 0.7} {
+		fords[int(float64(length)*f)+g.rng.Intn(3)-1] = true
+	}
+
+	prev := -1
+	for i := 0; i < length; i++ {
+		meander := (g.moisture.FBM(float64(i)/20, phase, 3) - 0.5) * float64(span) * 0.4
+		center := int(base + meander)
+		kind := TerrainWater
+		if fords[i] {
+			kind = TerrainFord
+		}
+
+		// Bridge jumps between consecutive steps so the river stays continuous.
+		lo, hi := center, center+width-1
+		if prev >= 0 {
+			lo, hi = min(lo, prev), max(hi, prev+width-1)
+		}
+		prev = center
+
+		for s := lo; s <= hi; s++ {
+			cx, cz := s, i
+			if !alongZ {
+				cx, cz = i, s
+			}
+			if g.inBounds(cx, cz) {
+				g.m.Tiles[g.index(cx, cz)] = kind
+			}
+		}
+	}
+}
+
+// castle places the starting castle on the driest, flattest spot nearest the
+// map centre and clears the ground inside it.
+func (g *generator) castle() {
+	size := int(math.Min(float64(g.m.Cols), float64(g.m.Rows)) * 0.3)
+	size = max(10, min(size, 40))
+	if size+2 > g.m.Cols || size+2 > g.m.Rows {
+		return
+	}
+
+	bestX, bestZ, bestScore := 0, 0, math.Inf(1)
+	for cz := 1; cz+size+1 < g.m.Rows; cz++ {
+		for cx := 1; cx+size+1 < g.m.Cols; cx++ {
+			water, rough := 0, 0.0
+			for z := cz - 1; z <= cz+size; z++ {
+				for x := cx - 1; x <= cx+size; x++ {
+					i := g.index(x, z)
+					if g.m.Tiles[i] == TerrainWater || g.m.Tiles[i] == TerrainFord {
+						water++
+					}
+					rough += math.Abs(g.m.Elevation[i] - 0.5)
+				}
+			}
+			dx := float64(cx+size/2) - float64(g.m.Cols)/2
+			dz := float64(cz+size/2) - float64(g.m.Rows)/2
+			score := float64(water)*1000 + rough + math.Sqrt(dx*dx+dz*dz)
+			if score < bestScore {
+				bestX, bestZ, bestScore = cx, cz, score
+			}
+		}
+	}
+
+	x0, z0 := bestX, bestZ
+	x1, z1 := bestX+size-1, bestZ+size-1
+	for z := z0 - 1; z <= z1+1; z++ {
+		for x := x0 - 1; x <= x1+1; x++ {
+			if g.inBounds(x, z) {
+				g.m.Tiles[g.index(x, z)] = TerrainGrass
+			}
+		}
+	}
+
+	cs := g.m.CellSize
+	gx := x0 + (size-gateWidth)/2
+	c := &g.m.Castle
+	c.MinX, c.MinZ = float64(x0)*cs, float64(z0)*cs
+	c.MaxX, c.MaxZ = float64(x1+1)*cs, float64(z1+1)*cs
+	c.Gate = Gate{
+		MinX: float64(gx) * cs,
+		MinZ: float64(z1) * cs,
+		MaxX: float64(gx+gateWidth) * cs,
+		MaxZ: float64(z1+1) * cs,
+	}
+	c.Walls = []constructions.Obstacle{
+		g.tileObstacle(x0+1, z0, x1-1, z0),         // north
+		g.tileObstacle(x0, z0+1, x0, z1-1),         // west
+		g.tileObstacle(x1, z0+1, x1, z1-1),         // east
+		g.tileObstacle(x0+1, z1, gx-1, z1),         // south, left of the gate
+		g.tileObstacle(gx+gateWidth, z1, x1-1, z1), // south, right of the gate
+	}
+	c.Towers = []constructions.Obstacle{
+		g.tileObstacle(x0, z0, x0, z0),
+		g.tileObstacle(x1, z0, x1, z0),
+		g.tileObstacle(x0, z1, x0, z1),
+		g.tileObstacle(x1, z1, x1, z1),
+	}
+}
+
+func (g *generator) insideCastle(x, z float64) bool {
+	c := g.m.Castle
+	return x >= c.MinX-2 && x <= c.MaxX+2 && z >= c.MinZ-2 && z <= c.MaxZ+2
+}
+
+func (g *generator) deposits() {
+	area := float64(g.m.Cols * g.m.Rows)
+	g.placeDeposits(DepositStone, int(area/600)+1, 2.0, 500, 1000, func(i int) bool {
+		return g.m.Tiles[i] == TerrainHill
+	})
+	g.placeDeposits(DepositIron, int(area/1200)+1, 1.5, 200, 500, func(i int) bool {
+		return g.m.Tiles[i] == TerrainHill && g.m.Elevation[i] > hillLevel+0.05
+	})
+	g.placeDeposits(DepositFarmland, int(area/500)+1, 3.0, 50, 100, func(i int) bool {
+		return g.m.Tiles[i] == TerrainGrass && g.m.Elevation[i] < 0.5
+	})
+}
+
+// placeDeposits picks up to count tiles matching accept, keeping deposits
+// spaced apart and out of the castle.
+func (g *generator) placeDeposits(kind DepositKind, count int, radius float64, minAmount, maxAmount int, accept func(i int) bool) {
+	var candidates []int
+	for i := range g.m.Tiles {
+		if accept(i) {
+			candidates = append(candidates, i)
+		}
+	}
+	g.rng.Shuffle(len(candidates), func(a, b int) {
+		candidates[a], candidates[b] = candidates[b], candidates[a]
+	})
+
+	placed := 0
+	for _, i := range candidates {
+		if placed >= count {
+			break
+		}
+		x := (float64(i%g.m.Cols) + 0.5) * g.m.CellSize
+		z := (float64(i/g.m.Cols) + 0.5) * g.m.CellSize
+		if g.insideCastle(x, z) || g.nearDeposit(x, z) {
+			continue
+		}
+		g.m.Deposits = append(g.m.Deposits, Deposit{
+			Kind:   kind,
+			X:      x,
+			Z:      z,
+			Radius: radius,
+			Amount: minAmount + g.rng.Intn(maxAmount-minAmount+1),
+		})
+		placed++
+	}
+}
+
+func (g *generator) nearDeposit(x, z float64) bool {
+	for _, d := range g.m.Deposits {
+		dx, dz := d.X-x, d.Z-z
+		if dx*dx+dz*dz < depositMargin*depositMargin {
+			return true
+		}
+	}
+	return false
+}
+
+// obstacles merges river tiles into row runs and appends the castle walls.
+func (g *generator) obstacles() {
+	for cz := 0; cz < g.m.Rows; cz++ {
+		start := -1
+		for cx := 0; cx <= g.m.Cols; cx++ {
+			water := cx < g.m.Cols && g.m.Tiles[g.index(cx, cz)] == TerrainWater
+			if water && start < 0 {
+				start = cx
+			}
+			if !water && start >= 0 {
+				g.m.Obstacles = append(g.m.Obstacles, g.tileObstacle(start, cz, cx-1, cz))
+				start = -1
+			}
+		}
+	}
+	g.m.Obstacles = append(g.m.Obstacles, g.m.Castle.Walls...)
+	g.m.Obstacles = append(g.m.Obstacles, g.m.Castle.Towers...)
+}
+
+// depositNodes is the node kind each deposit is split into.
+var depositNodes = map[DepositKind]resources.Kind{
+	DepositStone: resources.KindRock,
+	DepositIron:  resources.KindOre,
+}
+
+// nodes scatters trees through the forest and splits each stone and iron
+// deposit into a few outcrops or veins.
+func (g *generator) nodes() {
+	taken := make([]bool, len(g.m.Tiles))
+	place := func(kind resources.Kind, cx, cz, amount int) bool {
+		if !g.inBounds(cx, cz) || g.m.Tiles[g.index(cx, cz)] == TerrainWater {
+			return false
+		}
+		x := (float64(cx) + 0.5) * g.m.CellSize
+		z := (float64(cz) + 0.5) * g.m.CellSize
+		if g.insideCastle(x, z) {
+			return false
+		}
+		for dz := -nodeSpacing; dz <= nodeSpacing; dz++ {
+			for dx := -nodeSpacing; dx <= nodeSpacing; dx++ {
+				if g.inBounds(cx+dx, cz+dz) && taken[g.index(cx+dx, cz+dz)] {
+					return false
+				}
+			}
+		}
+		n, err := resources.New(kind, g.tileObstacle(cx, cz, cx, cz), amount)
+		if err != nil {
+			panic(err)
+		}
+		taken[g.index(cx, cz)] = true
+		g.m.Nodes = append(g.m.Nodes, n)
+		return true
+	}
+
+	for _, d := range g.m.Deposits {
+		kind, ok := depositNodes[d.Kind]
+		if !ok {
+			continue
+		}
+		const parts = 3
+		for range parts {
+			a := g.rng.Float64() * 2 * math.Pi
+			r := g.rng.Float64() * d.Radius
+			place(kind, int((d.X+r*math.Cos(a))/g.m.CellSize), int((d.Z+r*math.Sin(a))/g.m.CellSize), d.Amount/parts)
+		}
+	}
+	for i, t := range g.m.Tiles {
+		if t == TerrainForest && g.rng.Float64() < treeChance {
+			place(resources.KindTree, i%g.m.Cols, i/g.m.Cols, 20+g.rng.Intn(21))
+		}
+	}
+}
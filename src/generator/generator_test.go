@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"veatla/simulator/src/constructions"
+)
+
+var seeds = []int64{1, 2, 3, 42, 123456}
+
+const size = 60
+
+func TestSameSeedSameMap(t *testing.T) {
+	for _, seed := range seeds {
+		a, b := Generate(seed, size, size), Generate(seed, size, size)
+		for _, part := range []struct {
+			name string
+			a, b any
+		}{
+			{"tiles", a.Tiles, b.Tiles},
+			{"deposits", a.Deposits, b.Deposits},
+			{"castle", a.Castle, b.Castle},
+			{"obstacles", a.Obstacles, b.Obstacles},
+			{"nodes", a.Nodes, b.Nodes},
+		} {
+			if !reflect.DeepEqual(part.a, part.b) {
+				t.Errorf("seed %d: %s differ between runs", seed, part.name)
+			}
+		}
+	}
+}
+
+func TestSeedsDiffer(t *testing.T) {
+	a, b := Generate(1, size, size), Generate(2, size, size)
+	if reflect.DeepEqual(a.Tiles, b.Tiles) && reflect.DeepEqual(a.Nodes, b.Nodes) {
+		t.Fatal("seeds 1 and 2 generated the same map")
+	}
+}
+
+// blocked marks the tiles that obstacles or nodes cover, and water.
+func blocked(m *Map) []bool {
+	out := make([]bool, len(m.Tiles))
+	cover := func(o constructions.Obstacle) {
+		for cz := int(o.MinZ / m.CellSize); float64(cz)*m.CellSize < o.MaxZ; cz++ {
+			for cx := int(o.MinX / m.CellSize); float64(cx)*m.CellSize < o.MaxX; cx++ {
+				if cx >= 0 && cz >= 0 && cx < m.Cols && cz < m.Rows {
+					out[cz*m.Cols+cx] = true
+				}
+			}
+		}
+	}
+	for _, o := range m.Obstacles {
+		cover(o)
+	}
+	for _, n := range m.Nodes {
+		cover(n.Obstacle)
+	}
+	for i, t := range m.Tiles {
+		if t == TerrainWater {
+			out[i] = true
+		}
+	}
+	return out
+}
+
+func TestGateOpenAndReachable(t *testing.T) {
+	for _, seed := range seeds {
+		m := Generate(seed, size, size)
+		gate := m.Castle.Gate
+		if gate == (Gate{}) {
+			t.Fatalf("seed %d: castle has no gate", seed)
+		}
+		walls := blocked(&m)
+		cs := m.CellSize
+		gz := int(gate.MinZ / cs)
+		for cx := int(gate.MinX / cs); float64(cx)*cs < gate.MaxX; cx++ {
+			if walls[gz*m.Cols+cx] {
+				t.Fatalf("seed %d: gate tile (%d, %d) is blocked", seed, cx, gz)
+			}
+		}
+
+		// Walk from just inside the gate and expect to reach the map edge.
+		start := (gz-1)*m.Cols + int(gate.MinX/cs)
+		seen := map[int]bool{start: true}
+		queue := []int{start}
+		edge := false
+		for len(queue) > 0 && !edge {
+			i := queue[0]
+			queue = queue[1:]
+			cx, cz := i%m.Cols, i/m.Cols
+			if cx == 0 || cz == 0 || cx == m.Cols-1 || cz == m.Rows-1 {
+				edge = true
+			}
+			for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+				nx, nz := cx+d[0], cz+d[1]
+				j := nz*m.Cols + nx
+				if nx < 0 || nz < 0 || nx >= m.Cols || nz >= m.Rows || walls[j] || seen[j] {
+					continue
+				}
+				seen[j] = true
+				queue = append(queue, j)
+			}
+		}
+		if !edge {
+			t.Errorf("seed %d: the bailey is cut off from the map edge", seed)
+		}
+	}
+}
+
+func TestNodesOnLandOutsideCastle(t *testing.T) {
+	for _, seed := range seeds {
+		m := Generate(seed, size, size)
+		if len(m.Nodes) == 0 {
+			t.Errorf("seed %d: no nodes", seed)
+		}
+		c := m.Castle
+		for _, n := range m.Nodes {
+			x, z := (n.MinX+n.MaxX)/2, (n.MinZ+n.MaxZ)/2
+			if m.TileAt(x, z) == TerrainWater {
+				t.Errorf("seed %d: %s node at (%.1f, %.1f) is in water", seed, n.Kind, x, z)
+			}
+			if x >= c.MinX && x <= c.MaxX && z >= c.MinZ && z <= c.MaxZ {
+				t.Errorf("seed %d: %s node at (%.1f, %.1f) is inside the castle", seed, n.Kind, x, z)
+			}
+		}
+	}
+}
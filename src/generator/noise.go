@@ -0,0 +1,58 @@
+package generator
+
+import "math"
+
+// valueNoise is a seeded 2D value noise with smooth interpolation.
+type valueNoise struct {
+	seed int64
+}
+
+func newValueNoise(seed int64) valueNoise {
+	return valueNoise{seed: seed}
+}
+
+// lattice returns a pseudo-random value in [0, 1) for an integer lattice point.
+func (n valueNoise) lattice(x, z int64) float64 {
+	h := uint64(n.seed) ^ uint64(x)*0x9E3779B97F4A7C15 ^ uint64(z)*0xC2B2AE3D27D4EB4F
+	h ^= h >> 33
+	h *= 0xFF51AFD7ED558CCD
+	h ^= h >> 33
+	h *= 0xC4CEB9FE1A85EC53
+	h ^= h >> 33
+	return float64(h>>11) / float64(1<<53)
+}
+
+func smoothstep(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// At samples noise at (x, z) in lattice units.
+func (n valueNoise) At(x, z float64) float64 {
+	x0 := math.Floor(x)
+	z0 := math.Floor(z)
+	tx := smoothstep(x - x0)
+	tz := smoothstep(z - z0)
+	ix, iz := int64(x0), int64(z0)
+
+	a := n.lattice(ix, iz)
+	b := n.lattice(ix+1, iz)
+	c := n.lattice(ix, iz+1)
+	d := n.lattice(ix+1, iz+1)
+	return lerp(lerp(a, b, tx), lerp(c, d, tx), tz)
+}
+
+// FBM sums several octaves of noise and normalizes the result to [0, 1).
+func (n valueNoise) FBM(x, z float64, octaves int) float64 {
+	sum, amp, norm, freq := 0.0, 1.0, 0.0, 1.0
+	for o := 0; o < octaves; o++ {
+		sum += n.At(x*freq, z*freq) * amp
+		norm += amp
+		amp *= 0.5
+		freq *= 2
+	}
+	return sum / norm
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/resources"
+)
+
+// Terrain is the surface type of one map tile.
+type Terrain uint8
+
+const (
+	TerrainGrass Terrain = iota
+	TerrainHill
+	TerrainForest
+	TerrainWater
+	TerrainFord
+)
+
+func (t Terrain) String() string {
+	switch t {
+	case TerrainHill:
+		return "hill"
+	case TerrainForest:
+		return "forest"
+	case TerrainWater:
+		return "water"
+	case TerrainFord:
+		return "ford"
+	default:
+		return "grass"
+	}
+}
+
+// DepositKind is the type of resource found at a deposit.
+type DepositKind string
+
+const (
+	DepositStone    DepositKind = "stone"
+	DepositIron     DepositKind = "iron"
+	DepositFarmland DepositKind = "farmland"
+)
+
+// Deposit is a resource site placed on the map.
+type Deposit struct {
+	Kind   DepositKind
+	X, Z   float64
+	Radius float64
+	Amount int
+}
+
+// Gate is the opening left in the castle wall.
+type Gate struct {
+	MinX, MinZ float64
+	MaxX, MaxZ float64
+}
+
+// Castle is the starting castle layout: an inner bailey enclosed by walls
+// with a tower at each corner.
+type Castle struct {
+	MinX, MinZ float64
+	MaxX, MaxZ float64
+	Walls      []constructions.Obstacle
+	Towers     []constructions.Obstacle
+	Gate       Gate
+}
+
+// Map is a generated castle region.
+type Map struct {
+	Seed          int64
+	Width, Height float64
+	CellSize      float64
+	Cols, Rows    int
+	Tiles         []Terrain
+	Elevation     []float64
+	Deposits      []Deposit
+	Castle        Castle
+	// Obstacles holds every blocking footprint (rivers, castle walls and
+	// towers).
+	Obstacles []constructions.Obstacle
+	// Nodes are the trees, rock outcrops and ore veins that can be gathered.
+	// Their footprints are not in Obstacles.
+	Nodes []resources.Node
+}
+
+// TileAt returns the terrain under a world position.
+func (m *Map) TileAt(x, z float64) Terrain {
+	cx, cz := int(x/m.CellSize), int(z/m.CellSize)
+	if cx < 0 || cz < 0 || cx >= m.Cols || cz >= m.Rows {
+		return TerrainWater
+	}
+	return m.Tiles[cz*m.Cols+cx]
+}
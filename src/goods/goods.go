@@ -0,0 +1,60 @@
+package goods
+
+import "sort"
+
+// Good identifies a kind of tradeable item.
+type Good string
+
+const (
+	Wood  Good = "wood"
+	Stone Good = "stone"
+	Iron  Good = "iron"
+	Grain Good = "grain"
+	Flour Good = "flour"
+	Bread Good = "bread"
+	Tools Good = "tools"
+	Cloth Good = "cloth"
+)
+
+type info struct {
+	weight float64
+	price  float64
+}
+
+// table holds the carry weight and base price in coins of one unit of each good.
+var table = map[Good]info{
+	Wood:  {weight: 2, price: 2},
+	Stone: {weight: 3, price: 3},
+	Iron:  {weight: 4, price: 8},
+	Grain: {weight: 1, price: 1},
+	Flour: {weight: 1, price: 2},
+	Bread: {weight: 0.5, price: 3},
+	Tools: {weight: 2, price: 15},
+	Cloth: {weight: 0.5, price: 6},
+}
+
+// Weight returns the carry weight of one unit of g. Unknown goods weigh 1.
+func Weight(g Good) float64 {
+	if i, ok := table[g]; ok {
+		return i.weight
+	}
+	return 1
+}
+
+// BasePrice returns the starting market price of one unit of g.
+func BasePrice(g Good) float64 {
+	if i, ok := table[g]; ok {
+		return i.price
+	}
+	return 1
+}
+
+// All returns every known good in a stable order.
+func All() []Good {
+	out := make([]Good, 0, len(table))
+	for g := range table {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
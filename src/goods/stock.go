@@ -0,0 +1,79 @@
+package goods
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Stock is a count of units per good. The zero value is read-only; use
+// make or a literal before adding.
+type Stock map[Good]int
+
+// Add puts n units of g into the stock.
+func (s Stock) Add(g Good, n int) {
+	if n <= 0 {
+		return
+	}
+	s[g] += n
+}
+
+// Take removes up to n units of g and returns how many were removed.
+func (s Stock) Take(g Good, n int) int {
+	have := s[g]
+	if n > have {
+		n = have
+	}
+	if n <= 0 {
+		return 0
+	}
+	if have == n {
+		delete(s, g)
+	} else {
+		s[g] = have - n
+	}
+	return n
+}
+
+// Count returns the units of g held.
+func (s Stock) Count(g Good) int {
+	return s[g]
+}
+
+// Weight returns the total carry weight of the stock.
+func (s Stock) Weight() float64 {
+	total := 0.0
+	for g, n := range s {
+		total += Weight(g) * float64(n)
+	}
+	return total
+}
+
+// Goods returns the goods held in a stable order.
+func (s Stock) Goods() []Good {
+	out := make([]Good, 0, len(s))
+	for g, n := range s {
+		if n > 0 {
+			out = append(out, g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Clone returns an independent copy of the stock.
+func (s Stock) Clone() Stock {
+	out := make(Stock, len(s))
+	for g, n := range s {
+		out[g] = n
+	}
+	return out
+}
+
+// Item is a pile of goods lying on the ground.
+type Item struct {
+	ID   uuid.UUID
+	X, Z float64
+	Good Good
+	Qty  int
+}
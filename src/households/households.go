@@ -0,0 +1,116 @@
+package households
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+
+	"veatla/simulator/src/goods"
+
+	"github.com/google/uuid"
+)
+
+// Household is the agents living in one house. Members share a budget and
+// a pantry kept at home.
+type Household struct {
+	ID uuid.UUID `json:"id"`
+	// Home is the house the household lives in.
+	Home    uuid.UUID   `json:"home"`
+	Members []uuid.UUID `json:"members"`
+	// Money is the shared budget, held under the household's ID.
+	Money int64 `json:"money"`
+	// Pantry is the food stored at home.
+	Pantry goods.Stock `json:"pantry"`
+}
+
+// New creates an empty household living in home.
+func New(id, home uuid.UUID) Household {
+	return Household{ID: id, Home: home, Pantry: goods.Stock{}}
+}
+
+// Has reports whether agent is a member.
+func (h *Household) Has(agent uuid.UUID) bool {
+	return slices.Contains(h.Members, agent)
+}
+
+// Join adds agent to the household.
+func (h *Household) Join(agent uuid.UUID) {
+	if !h.Has(agent) {
+		h.Members = append(h.Members, agent)
+	}
+}
+
+// Leave removes agent and reports whether anyone is left.
+func (h *Household) Leave(agent uuid.UUID) bool {
+	if i := slices.Index(h.Members, agent); i >= 0 {
+		h.Members = slices.Delete(h.Members, i, i+1)
+	}
+	return len(h.Members) > 0
+}
+
+// clone copies h so a snapshot does not share members or pantry with the
+// simulation.
+func (h Household) clone() Household {
+	h.Members = slices.Clone(h.Members)
+	pantry := make(goods.Stock, len(h.Pantry))
+	for g, n := range h.Pantry {
+		pantry[g] = n
+	}
+	h.Pantry = pantry
+	return h
+}
+
+// Directory is a snapshot of the households published for the HTTP API. It
+// is safe for concurrent use.
+type Directory struct {
+	mu         sync.Mutex
+	households []Household
+}
+
+func NewDirectory() *Directory {
+	return &Directory{}
+}
+
+// Publish replaces the snapshot with copies of hs.
+func (d *Directory) Publish(hs []Household) {
+	out := make([]Household, len(hs))
+	for i, h := range hs {
+		out[i] = h.clone()
+	}
+	d.mu.Lock()
+	d.households = out
+	d.mu.Unlock()
+}
+
+// Households returns the published households. An agent narrows them to the
+// household it belongs to.
+func (d *Directory) Households(agent uuid.UUID) []Household {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]Household, 0)
+	for _, h := range d.households {
+		if agent == uuid.Nil || h.Has(agent) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// ServeHTTP writes the households as JSON. An "agent" query parameter
+// narrows them to that agent's household.
+func (d *Directory) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var agent uuid.UUID
+	if s := req.URL.Query().Get("agent"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		agent = id
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(d.Households(agent)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
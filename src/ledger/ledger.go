@@ -0,0 +1,132 @@
+package ledger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Reasons attached to entries.
+const (
+	ReasonInitial     = "initial"
+	ReasonImmigrated  = "immigrated"
+	ReasonEmigrated   = "emigrated"
+	ReasonInheritance = "inheritance"
+	ReasonMint        = "mint"
+	ReasonTrade       = "trade"
+	ReasonWage        = "wage"
+	ReasonRent        = "rent"
+	ReasonIncomeTax   = "incomeTax"
+	ReasonSalesTax    = "salesTax"
+	ReasonToll        = "toll"
+	ReasonHousehold   = "household"
+)
+
+var (
+	// External is the outside world. Minted coins, immigrants' savings and
+	// the funds a scenario starts with come from it; money leaving with
+	// emigrants goes to it.
+	External = uuid.Nil
+	// Treasury is the castle's account.
+	Treasury = uuid.NewSHA1(uuid.NameSpaceOID, []byte("castle-treasury"))
+)
+
+// Entry is one transfer of coins.
+type Entry struct {
+	Tick   int       `json:"tick"`
+	From   uuid.UUID `json:"from"`
+	To     uuid.UUID `json:"to"`
+	Amount int64     `json:"amount"`
+	Reason string    `json:"reason"`
+}
+
+// Ledger records every transfer. It keeps a bounded history for queries and
+// the running net flow with the outside world for conservation checks. It
+// is safe for concurrent use.
+type Ledger struct {
+	mu       sync.Mutex
+	entries  []Entry
+	next     int
+	full     bool
+	external int64
+	minted   int64
+}
+
+// New creates a ledger that keeps the last n entries.
+func New(n int) *Ledger {
+	return &Ledger{entries: make([]Entry, max(n, 1))}
+}
+
+// Record appends an entry.
+func (l *Ledger) Record(e Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e.From == External {
+		l.external += e.Amount
+		if e.Reason == ReasonMint {
+			l.minted += e.Amount
+		}
+	}
+	if e.To == External {
+		l.external -= e.Amount
+	}
+	l.entries[l.next] = e
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Supply is the money that should exist inside the world: everything that
+// came in from outside, including minting, minus everything that left.
+func (l *Ledger) Supply() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.external
+}
+
+// Minted is the total coins created by mints.
+func (l *Ledger) Minted() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.minted
+}
+
+// Entries returns the stored entries touching account, oldest first. A nil
+// account returns every entry.
+func (l *Ledger) Entries(account uuid.UUID) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	all := l.entries[:l.next]
+	if l.full {
+		all = append(append([]Entry(nil), l.entries[l.next:]...), l.entries[:l.next]...)
+	}
+	out := make([]Entry, 0)
+	for _, e := range all {
+		if account == uuid.Nil || e.From == account || e.To == account {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// ServeHTTP writes entries as JSON. An "account" query parameter narrows
+// them to one agent or building.
+func (l *Ledger) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var account uuid.UUID
+	if s := req.URL.Query().Get("account"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		account = id
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(l.Entries(account)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
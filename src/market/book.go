@@ -0,0 +1,150 @@
+package market
+
+import (
+	"sort"
+
+	"veatla/simulator/src/goods"
+
+	"github.com/google/uuid"
+)
+
+// Side is whether an order buys or sells.
+type Side int
+
+const (
+	Buy Side = iota
+	Sell
+)
+
+// Order is a limit order for one good. Limit is the highest price a buyer
+// pays or the lowest price a seller accepts, in coins per unit.
+type Order struct {
+	ID      uint64
+	Owner   uuid.UUID
+	Side    Side
+	Good    goods.Good
+	Qty     int
+	Limit   int64
+	Expires int
+}
+
+// Fill is one match between a buyer and a seller at the resting order's price.
+type Fill struct {
+	Good   goods.Good
+	Buyer  uuid.UUID
+	Seller uuid.UUID
+	Qty    int
+	Price  int64
+}
+
+// Settle moves the goods and money of a fill as it is matched. If either
+// party cannot take part, it changes nothing and reports which one it is.
+type Settle func(f Fill) (buyerOK, sellerOK bool)
+
+// Book holds resting orders for one good, best price first and oldest
+// first among equal prices.
+type Book struct {
+	buys  []Order
+	sells []Order
+}
+
+// Place matches o against the opposite side and rests any remainder. Each
+// fill is settled before the book changes: a resting order whose owner
+// cannot settle is dropped and matching goes on, while o is dropped if its
+// own owner cannot. A nil settle accepts every fill.
+func (b *Book) Place(o Order, settle Settle) []Fill {
+	var fills []Fill
+	opposite := &b.sells
+	crosses := func(r Order) bool { return r.Limit <= o.Limit }
+	if o.Side == Sell {
+		opposite = &b.buys
+		crosses = func(r Order) bool { return r.Limit >= o.Limit }
+	}
+
+	for o.Qty > 0 && len(*opposite) > 0 && crosses((*opposite)[0]) {
+		r := &(*opposite)[0]
+		if r.Owner == o.Owner {
+			break
+		}
+		n := min(o.Qty, r.Qty)
+		f := Fill{Good: o.Good, Qty: n, Price: r.Limit, Buyer: o.Owner, Seller: r.Owner}
+		if o.Side == Sell {
+			f.Buyer, f.Seller = r.Owner, o.Owner
+		}
+		if settle != nil {
+			buyerOK, sellerOK := settle(f)
+			incomingOK, restingOK := buyerOK, sellerOK
+			if o.Side == Sell {
+				incomingOK, restingOK = sellerOK, buyerOK
+			}
+			if !restingOK {
+				*opposite = (*opposite)[1:]
+			}
+			if !incomingOK {
+				return fills
+			}
+			if !restingOK {
+				continue
+			}
+		}
+		fills = append(fills, f)
+		o.Qty -= n
+		r.Qty -= n
+		if r.Qty == 0 {
+			*opposite = (*opposite)[1:]
+		}
+	}
+
+	if o.Qty > 0 {
+		b.rest(o)
+	}
+	return fills
+}
+
+func (b *Book) rest(o Order) {
+	side := &b.buys
+	better := func(a, c Order) bool { return a.Limit > c.Limit }
+	if o.Side == Sell {
+		side = &b.sells
+		better = func(a, c Order) bool { return a.Limit < c.Limit }
+	}
+	i := sort.Search(len(*side), func(i int) bool { return better(o, (*side)[i]) })
+	*side = append(*side, Order{})
+	copy((*side)[i+1:], (*side)[i:])
+	(*side)[i] = o
+}
+
+// Cancel removes every order placed by owner.
+func (b *Book) Cancel(owner uuid.UUID) {
+	keep := func(o Order) bool { return o.Owner != owner }
+	b.buys = filter(b.buys, keep)
+	b.sells = filter(b.sells, keep)
+}
+
+// Expire removes orders whose expiry tick has passed.
+func (b *Book) Expire(tick int) {
+	keep := func(o Order) bool { return o.Expires > tick }
+	b.buys = filter(b.buys, keep)
+	b.sells = filter(b.sells, keep)
+}
+
+// Depth returns the total quantity resting on each side.
+func (b *Book) Depth() (bids, asks int) {
+	for _, o := range b.buys {
+		bids += o.Qty
+	}
+	for _, o := range b.sells {
+		asks += o.Qty
+	}
+	return bids, asks
+}
+
+func filter(orders []Order, keep func(Order) bool) []Order {
+	out := orders[:0]
+	for _, o := range orders {
+		if keep(o) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
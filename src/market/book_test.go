@@ -0,0 +1,125 @@
+package market
+
+import (
+	"slices"
+	"testing"
+
+	"veatla/simulator/src/goods"
+
+	"github.com/google/uuid"
+)
+
+var (
+	alice = uuid.UUID{15: 1}
+	bob   = uuid.UUID{15: 2}
+	carol = uuid.UUID{15: 3}
+)
+
+func order(owner uuid.UUID, side Side, qty int, limit int64) Order {
+	return Order{Owner: owner, Side: side, Good: goods.Bread, Qty: qty, Limit: limit, Expires: 100}
+}
+
+func TestMatching(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		resting   []Order
+		incoming  Order
+		want      []Fill
+		bids, ask int
+	}{
+		{
+			name:     "no cross",
+			resting:  []Order{order(alice, Sell, 2, 5)},
+			incoming: order(bob, Buy, 1, 4),
+			bids:     1, ask: 2,
+		},
+		{
+			name:     "at resting price",
+			resting:  []Order{order(alice, Sell, 2, 3)},
+			incoming: order(bob, Buy, 1, 5),
+			want:     []Fill{{Good: goods.Bread, Buyer: bob, Seller: alice, Qty: 1, Price: 3}},
+			ask:      1,
+		},
+		{
+			name:     "best price first",
+			resting:  []Order{order(alice, Sell, 1, 4), order(carol, Sell, 1, 2)},
+			incoming: order(bob, Buy, 3, 4),
+			want: []Fill{
+				{Good: goods.Bread, Buyer: bob, Seller: carol, Qty: 1, Price: 2},
+				{Good: goods.Bread, Buyer: bob, Seller: alice, Qty: 1, Price: 4},
+			},
+			bids: 1,
+		},
+		{
+			name:     "oldest first at equal price",
+			resting:  []Order{order(alice, Buy, 1, 3), order(carol, Buy, 1, 3)},
+			incoming: order(bob, Sell, 1, 3),
+			want:     []Fill{{Good: goods.Bread, Buyer: alice, Seller: bob, Qty: 1, Price: 3}},
+			bids:     1,
+		},
+		{
+			name:     "no self trade",
+			resting:  []Order{order(alice, Sell, 1, 2), order(carol, Sell, 1, 3)},
+			incoming: order(alice, Buy, 1, 5),
+			bids:     1, ask: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var b Book
+			for _, o := range tc.resting {
+				b.Place(o, nil)
+			}
+			got := b.Place(tc.incoming, nil)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("fills = %+v, want %+v", got, tc.want)
+			}
+			if bids, asks := b.Depth(); bids != tc.bids || asks != tc.ask {
+				t.Errorf("depth = %d/%d, want %d/%d", bids, asks, tc.bids, tc.ask)
+			}
+		})
+	}
+}
+
+func TestSettleFailure(t *testing.T) {
+	// alice cannot deliver; bob cannot pay.
+	settle := func(f Fill) (bool, bool) { return f.Buyer != bob, f.Seller != alice }
+
+	var b Book
+	b.Place(order(alice, Sell, 1, 2), nil)
+	b.Place(order(carol, Sell, 1, 3), nil)
+
+	// The buyer skips alice's order, which is dropped, and fills with carol.
+	buyer := uuid.UUID{15: 4}
+	got := b.Place(order(buyer, Buy, 2, 5), settle)
+	want := []Fill{{Good: goods.Bread, Buyer: buyer, Seller: carol, Qty: 1, Price: 3}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("fills = %+v, want %+v", got, want)
+	}
+	if bids, asks := b.Depth(); bids != 1 || asks != 0 {
+		t.Fatalf("depth = %d/%d, want 1/0", bids, asks)
+	}
+
+	// An incoming order its owner cannot settle is dropped, leaving the
+	// book as it was.
+	b = Book{}
+	b.Place(order(carol, Sell, 1, 2), nil)
+	if got := b.Place(order(bob, Buy, 1, 5), settle); len(got) != 0 {
+		t.Fatalf("unpaid order filled: %+v", got)
+	}
+	if bids, asks := b.Depth(); bids != 0 || asks != 1 {
+		t.Fatalf("depth = %d/%d, want 0/1", bids, asks)
+	}
+}
+
+func TestFailedFillsDoNotMovePrice(t *testing.T) {
+	m := New(uuid.UUID{15: 9})
+	base := m.Price(goods.Bread)
+	refuse := func(Fill) (bool, bool) { return true, false }
+	m.Place(alice, Sell, goods.Bread, 1, int64(base*10), 100, nil)
+	m.Place(m.ID, Buy, goods.Bread, 1, int64(base*10), 100, refuse)
+	m.Adjust(1)
+	// Only alice's unmatched supply counts; no trade at ten times the price.
+	if p := m.Price(goods.Bread); p >= base {
+		t.Fatalf("price = %v, want below %v", p, base)
+	}
+}
@@ -0,0 +1,95 @@
+package market
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// PricePoint is one market's reference prices at the end of a tick.
+type PricePoint struct {
+	Tick   int                `json:"tick"`
+	Market uuid.UUID          `json:"market"`
+	Prices map[string]float64 `json:"prices"`
+}
+
+// History keeps the last n price points. It is safe for concurrent use so
+// the HTTP API can read it while the simulation records.
+type History struct {
+	mu     sync.Mutex
+	points []PricePoint
+	next   int
+	full   bool
+}
+
+// NewHistory creates a history that keeps the last n points.
+func NewHistory(n int) *History {
+	return &History{points: make([]PricePoint, max(n, 1))}
+}
+
+// Record stores m's current prices for tick.
+func (h *History) Record(tick int, m *Market) {
+	prices := make(map[string]float64, len(m.prices))
+	for g, p := range m.prices {
+		prices[string(g)] = p
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.points[h.next] = PricePoint{Tick: tick, Market: m.ID, Prices: prices}
+	h.next = (h.next + 1) % len(h.points)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Points returns the stored points, oldest first.
+func (h *History) Points() []PricePoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]PricePoint(nil), h.points[:h.next]...)
+	}
+	out := make([]PricePoint, 0, len(h.points))
+	out = append(out, h.points[h.next:]...)
+	return append(out, h.points[:h.next]...)
+}
+
+// ServeHTTP writes the history as JSON. Optional "good" and "market" query
+// parameters narrow it down to one good or one market.
+func (h *History) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	good := req.URL.Query().Get("good")
+	var market uuid.UUID
+	if s := req.URL.Query().Get("market"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		market = id
+	}
+
+	points := h.Points()
+	out := points[:0]
+	for _, p := range points {
+		if market != uuid.Nil && p.Market != market {
+			continue
+		}
+		if good != "" {
+			price, ok := p.Prices[good]
+			if !ok {
+				continue
+			}
+			p.Prices = map[string]float64{good: price}
+		}
+		out = append(out, p)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,138 @@
+package market
+
+import (
+	"math"
+
+	"veatla/simulator/src/goods"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// adjustRate is the largest relative price change per Adjust when only
+	// buyers or only sellers showed up.
+	adjustRate = 0.05
+	// tradeWeight is how far the price moves towards the average traded
+	// price per Adjust.
+	tradeWeight = 0.3
+	// minPrice and maxMultiple bound prices relative to the base price.
+	minPrice    = 0.1
+	maxMultiple = 20
+)
+
+type flow struct {
+	supply, demand int
+	qty            int
+	value          int64
+}
+
+// Market is a trading place with one order book per good and a reference
+// price that follows supply and demand.
+type Market struct {
+	ID     uuid.UUID
+	books  map[goods.Good]*Book
+	prices map[goods.Good]float64
+	flows  map[goods.Good]*flow
+	nextID uint64
+}
+
+// New creates a market with every known good at its base price.
+func New(id uuid.UUID) *Market {
+	m := &Market{
+		ID:     id,
+		books:  make(map[goods.Good]*Book),
+		prices: make(map[goods.Good]float64),
+		flows:  make(map[goods.Good]*flow),
+	}
+	for _, g := range goods.All() {
+		m.prices[g] = goods.BasePrice(g)
+	}
+	return m
+}
+
+// Place submits a limit order and returns the fills it produced, each
+// already settled by settle. Fills that fail to settle leave no trace in the
+// book or the traded prices. Orders placed by the market itself are quotes
+// around its price and do not count as supply or demand.
+func (m *Market) Place(owner uuid.UUID, side Side, g goods.Good, qty int, limit int64, expires int, settle Settle) []Fill {
+	if qty <= 0 || limit <= 0 {
+		return nil
+	}
+	m.nextID++
+	f := m.flow(g)
+	switch {
+	case owner == m.ID:
+	case side == Buy:
+		f.demand += qty
+	default:
+		f.supply += qty
+	}
+	fills := m.book(g).Place(Order{ID: m.nextID, Owner: owner, Side: side, Good: g, Qty: qty, Limit: limit, Expires: expires}, settle)
+	for _, fl := range fills {
+		f.qty += fl.Qty
+		f.value += int64(fl.Qty) * fl.Price
+	}
+	return fills
+}
+
+// Cancel removes every order placed by owner.
+func (m *Market) Cancel(owner uuid.UUID) {
+	for _, b := range m.books {
+		b.Cancel(owner)
+	}
+}
+
+// Price returns the current reference price of g.
+func (m *Market) Price(g goods.Good) float64 {
+	if p, ok := m.prices[g]; ok {
+		return p
+	}
+	return goods.BasePrice(g)
+}
+
+// Prices returns a copy of every reference price.
+func (m *Market) Prices() map[goods.Good]float64 {
+	out := make(map[goods.Good]float64, len(m.prices))
+	for g, p := range m.prices {
+		out[g] = p
+	}
+	return out
+}
+
+// Adjust expires stale orders and moves each price by the imbalance between
+// quantity demanded and supplied since the last call, then towards the
+// average traded price.
+func (m *Market) Adjust(tick int) {
+	for _, b := range m.books {
+		b.Expire(tick)
+	}
+	for g, f := range m.flows {
+		p := m.Price(g)
+		if total := f.supply + f.demand; total > 0 {
+			p *= 1 + adjustRate*float64(f.demand-f.supply)/float64(total)
+		}
+		if f.qty > 0 {
+			p += tradeWeight * (float64(f.value)/float64(f.qty) - p)
+		}
+		m.prices[g] = math.Min(math.Max(p, minPrice), goods.BasePrice(g)*maxMultiple)
+		*f = flow{}
+	}
+}
+
+func (m *Market) book(g goods.Good) *Book {
+	b, ok := m.books[g]
+	if !ok {
+		b = &Book{}
+		m.books[g] = b
+	}
+	return b
+}
+
+func (m *Market) flow(g goods.Good) *flow {
+	f, ok := m.flows[g]
+	if !ok {
+		f = &flow{}
+		m.flows[g] = f
+	}
+	return f
+}
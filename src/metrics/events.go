@@ -0,0 +1,17 @@
+package metrics
+
+import "veatla/simulator/src/events"
+
+// SubscribeEvents counts bus events: stuck agents and path failures feed the
+// core counters, and every kind gets its own sim_events_<kind>_total counter.
+func SubscribeEvents(r *Recorder, bus *events.Bus) (unsubscribe func()) {
+	return bus.Subscribe(func(rec events.Record) {
+		switch rec.Event.Kind() {
+		case events.KindAgentStuck:
+			r.Add(StuckEvents, 1)
+		case events.KindPathFailed:
+			r.Add(AStarFailures, 1)
+		}
+		r.Add("sim_events_"+string(rec.Event.Kind())+"_total", 1)
+	})
+}
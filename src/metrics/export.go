@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// WritePrometheus writes current values in the Prometheus text format.
+func (r *Recorder) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.metrics {
+		if m.help != "" {
+			if _, err := fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n%s %s\n", m.name, m.kind, m.name, formatValue(m.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the history with one column per metric.
+func (r *Recorder) WriteCSV(w io.Writer) error {
+	names := r.names()
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"tick"}, names...)); err != nil {
+		return err
+	}
+	for _, row := range r.History() {
+		rec := make([]string, 0, len(names)+1)
+		rec = append(rec, strconv.Itoa(row.Tick))
+		for _, name := range names {
+			rec = append(rec, formatValue(row.Values[name]))
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSONL writes the history as one JSON object per line.
+func (r *Recorder) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, row := range r.History() {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves the Prometheus exposition. A "format" query parameter of
+// csv or jsonl returns the history dump instead.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var err error
+	switch req.URL.Query().Get("format") {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		err = r.WriteCSV(w)
+	case "jsonl":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		err = r.WriteJSONL(w)
+	default:
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		err = r.WritePrometheus(w)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
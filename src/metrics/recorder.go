@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"sync"
+)
+
+// Kind tells exporters how a metric behaves over time.
+type Kind int
+
+const (
+	Gauge Kind = iota
+	Counter
+)
+
+func (k Kind) String() string {
+	if k == Counter {
+		return "counter"
+	}
+	return "gauge"
+}
+
+type metric struct {
+	name  string
+	help  string
+	kind  Kind
+	value float64
+}
+
+// Row is the snapshot of every metric at the end of one tick.
+type Row struct {
+	Tick   int                `json:"tick"`
+	Values map[string]float64 `json:"values"`
+}
+
+// Recorder holds the current value of each registered metric and a bounded
+// per-tick history for offline export. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	metrics []*metric
+	byName  map[string]*metric
+	history []Row
+	next    int
+	full    bool
+}
+
+// NewRecorder creates a recorder that keeps the last historySize rows.
+func NewRecorder(historySize int) *Recorder {
+	return &Recorder{
+		byName:  make(map[string]*metric),
+		history: make([]Row, max(historySize, 1)),
+	}
+}
+
+// Register declares a metric. Registering an existing name is a no-op.
+func (r *Recorder) Register(name, help string, kind Kind) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byName[name]; ok {
+		return
+	}
+	m := &metric{name: name, help: help, kind: kind}
+	r.metrics = append(r.metrics, m)
+	r.byName[name] = m
+}
+
+// Set overwrites a metric's value. Unregistered names are registered as gauges.
+func (r *Recorder) Set(name string, v float64) {
+	r.mu.Lock()
+	r.get(name).value = v
+	r.mu.Unlock()
+}
+
+// Add increments a metric. Unregistered names are registered as counters.
+func (r *Recorder) Add(name string, delta float64) {
+	r.mu.Lock()
+	m, ok := r.byName[name]
+	if !ok {
+		m = &metric{name: name, kind: Counter}
+		r.metrics = append(r.metrics, m)
+		r.byName[name] = m
+	}
+	m.value += delta
+	r.mu.Unlock()
+}
+
+func (r *Recorder) get(name string) *metric {
+	m, ok := r.byName[name]
+	if !ok {
+		m = &metric{name: name, kind: Gauge}
+		r.metrics = append(r.metrics, m)
+		r.byName[name] = m
+	}
+	return m
+}
+
+// Flush stores the current values as the history row for tick.
+func (r *Recorder) Flush(tick int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values := make(map[string]float64, len(r.metrics))
+	for _, m := range r.metrics {
+		values[m.name] = m.value
+	}
+	r.history[r.next] = Row{Tick: tick, Values: values}
+	r.next = (r.next + 1) % len(r.history)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// History returns the stored rows, oldest first.
+func (r *Recorder) History() []Row {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]Row(nil), r.history[:r.next]...)
+	}
+	out := make([]Row, 0, len(r.history))
+	out = append(out, r.history[r.next:]...)
+	return append(out, r.history[:r.next]...)
+}
+
+// names returns metric names in registration order.
+func (r *Recorder) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]string, len(r.metrics))
+	for i, m := range r.metrics {
+		out[i] = m.name
+	}
+	return out
+}
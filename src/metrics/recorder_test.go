@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+
+	"veatla/simulator/src/events"
+)
+
+func TestHistoryKeepsNewestRows(t *testing.T) {
+	r := NewRecorder(3)
+	r.Register(Agents, "", Gauge)
+	for tick := 1; tick <= 5; tick++ {
+		r.Set(Agents, float64(tick*10))
+		r.Flush(tick)
+	}
+	var ticks []int
+	for _, row := range r.History() {
+		ticks = append(ticks, row.Tick)
+		if row.Values[Agents] != float64(row.Tick*10) {
+			t.Errorf("tick %d: agents = %v", row.Tick, row.Values[Agents])
+		}
+	}
+	if !slices.Equal(ticks, []int{3, 4, 5}) {
+		t.Fatalf("history ticks = %v, want [3 4 5]", ticks)
+	}
+}
+
+func TestExports(t *testing.T) {
+	r := NewRecorder(10)
+	r.Register(Agents, "Agents alive.", Gauge)
+	r.Add(AStarCalls, 2)
+	r.Set(Agents, 4)
+	r.Flush(1)
+	r.Add(AStarCalls, 3)
+	r.Flush(2)
+
+	var prom bytes.Buffer
+	if err := r.WritePrometheus(&prom); err != nil {
+		t.Fatal(err)
+	}
+	want := "# HELP sim_agents Agents alive.\n# TYPE sim_agents gauge\nsim_agents 4\n" +
+		"# TYPE sim_astar_calls_total counter\nsim_astar_calls_total 5\n"
+	if prom.String() != want {
+		t.Errorf("prometheus =\n%s\nwant\n%s", prom.String(), want)
+	}
+
+	var dump bytes.Buffer
+	if err := r.WriteCSV(&dump); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&dump).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRows := [][]string{{"tick", Agents, AStarCalls}, {"1", "4", "2"}, {"2", "4", "5"}}
+	if !slices.EqualFunc(rows, wantRows, slices.Equal) {
+		t.Errorf("csv = %v, want %v", rows, wantRows)
+	}
+
+	var lines bytes.Buffer
+	if err := r.WriteJSONL(&lines); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSpace(lines.String()), "\n")
+	if len(got) != 2 || got[1] != `{"tick":2,"values":{"sim_agents":4,"sim_astar_calls_total":5}}` {
+		t.Errorf("jsonl = %q", got)
+	}
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	r := NewRecorder(1)
+	bus := events.NewBus(4)
+	unsubscribe := SubscribeEvents(r, bus)
+	bus.Publish(events.AgentStuck{})
+	bus.Publish(events.PathFailed{})
+	bus.Publish(events.PathFailed{})
+	unsubscribe()
+	bus.Publish(events.PathFailed{})
+
+	r.Flush(1)
+	values := r.History()[0].Values
+	for name, want := range map[string]float64{
+		StuckEvents:                   1,
+		AStarFailures:                 2,
+		"sim_events_pathFailed_total": 2,
+		"sim_events_agentStuck_total": 1,
+	} {
+		if values[name] != want {
+			t.Errorf("%s = %v, want %v", name, values[name], want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"time"
+
+	"veatla/simulator/src/world"
+)
+
+const (
+	TickDuration   = "sim_tick_duration_seconds"
+	Agents         = "sim_agents"
+	AgentsMoved    = "sim_agents_moved"
+	AStarCalls     = "sim_astar_calls_total"
+	AStarFailures  = "sim_astar_failures_total"
+	AStarExpansion = "sim_astar_expansions_total"
+	StuckEvents    = "sim_stuck_events_total"
+	BroadcastBytes = "ws_broadcast_bytes_total"
+	Clients        = "ws_clients"
+)
+
+// RegisterSimulation declares the core simulation and server metrics.
+func RegisterSimulation(r *Recorder) {
+	r.Register(TickDuration, "Wall time spent in the last tick.", Gauge)
+	r.Register(Agents, "Agents alive in the world.", Gauge)
+	r.Register(AgentsMoved, "Agents that moved during the last tick.", Gauge)
+	r.Register(AStarCalls, "A* searches started.", Counter)
+	r.Register(AStarFailures, "A* searches that found no path.", Counter)
+	r.Register(AStarExpansion, "A* nodes expanded.", Counter)
+	r.Register(StuckEvents, "Times an agent was detected as stuck.", Counter)
+	r.Register(BroadcastBytes, "Bytes written to WebSocket clients.", Counter)
+	r.Register(Clients, "Connected WebSocket clients.", Gauge)
+}
+
+// RecordTick adds the stats of one world tick. Stuck events and path failures
+// are counted from the event bus, see SubscribeEvents.
+func RecordTick(r *Recorder, dur time.Duration, s world.TickStats) {
+	r.Set(TickDuration, dur.Seconds())
+	r.Set(Agents, float64(s.Agents))
+	r.Set(AgentsMoved, float64(s.AgentsMoved))
+	r.Add(AStarCalls, float64(s.PathRequests))
+	r.Add(AStarExpansion, float64(s.PathExpansions))
+}
@@ -9,19 +9,26 @@ import (
 const maxPathLen = 1000
 
 // AStarPath finds a path between two world coordinates with obstacle avoidance and offset.
-func AStarPath(startX, startZ, goalX, goalZ float64, q worldQuery.WorldQuery, obstacleOffset float64) ([]PathPoint, bool, float64) {
+func AStarPath(startX, startZ, goalX, goalZ float64, q worldQuery.WorldQuery, who worldQuery.Passer, obstacleOffset float64) ([]PathPoint, bool, float64) {
+	path, found, total, _ := AStarPathWithStats(startX, startZ, goalX, goalZ, q, who, obstacleOffset)
+	return path, found, total
+}
+
+// AStarPathWithStats is AStarPath that also reports search statistics.
+func AStarPathWithStats(startX, startZ, goalX, goalZ float64, q worldQuery.WorldQuery, who worldQuery.Passer, obstacleOffset float64) ([]PathPoint, bool, float64, SearchStats) {
 	const cellSize = 2.0
-	rawPath, found, total := aStarSearch(startX, startZ, goalX, goalZ, q, obstacleOffset, cellSize)
+	rawPath, found, total, expansions := aStarSearch(startX, startZ, goalX, goalZ, q, who, obstacleOffset, cellSize)
+	stats := SearchStats{Expansions: expansions}
 	if !found || len(rawPath) == 0 {
-		return nil, false, 0.0
+		return nil, false, 0.0, stats
 	}
-	if !validatePathSimple(rawPath, q, obstacleOffset, cellSize) {
-		return nil, false, 0.0
+	if !validatePathSimple(rawPath, q, who, obstacleOffset, cellSize) {
+		return nil, false, 0.0, stats
 	}
-	return rawPath, true, total
+	return rawPath, true, total, stats
 }
 
-func aStarSearch(startX, startZ, goalX, goalZ float64, q worldQuery.WorldQuery, obstacleOffset, cellSize float64) ([]PathPoint, bool, float64) {
+func aStarSearch(startX, startZ, goalX, goalZ float64, q worldQuery.WorldQuery, who worldQuery.Passer, obstacleOffset, cellSize float64) ([]PathPoint, bool, float64, int) {
 	openSet := make(map[string]*AStarNode)
 	closedSet := make(map[string]*AStarNode)
 	pq := &AStarPriorityQueue{}
@@ -52,17 +59,17 @@ func aStarSearch(startX, startZ, goalX, goalZ float64, q worldQuery.WorldQuery,
 		}
 
 		if distance(current.point.X, current.point.Z, goalX, goalZ) < cellSize {
-			return reconstructAStarPath(current), true, current.Cost
+			return reconstructAStarPath(current), true, current.Cost, pathCount
 		}
 
-		for _, neighbor := range getNeighbors(current.point, cellSize, obstacleOffset, q) {
+		for _, neighbor := range getNeighbors(current.point, cellSize, obstacleOffset, q, who) {
 			neighborKey := pointKey(neighbor.X, neighbor.Z)
 
 			if _, inClosed := closedSet[neighborKey]; inClosed {
 				continue
 			}
 
-			moveCost := distance(current.point.X, current.point.Z, neighbor.X, neighbor.Z)
+			moveCost := distance(current.point.X, current.point.Z, neighbor.X, neighbor.Z) * q.MoveCost(neighbor.X, neighbor.Z)
 			newCost := current.Cost + moveCost
 
 			if existingNode, inOpen := openSet[neighborKey]; inOpen {
@@ -87,13 +94,13 @@ func aStarSearch(startX, startZ, goalX, goalZ float64, q worldQuery.WorldQuery,
 	}
 
 	if bestNode != nil && heuristic(bestNode.point.X, bestNode.point.Z, goalX, goalZ) < cellSize*3 {
-		return reconstructAStarPath(bestNode), true, bestNode.Cost
+		return reconstructAStarPath(bestNode), true, bestNode.Cost, pathCount
 	}
 
-	return nil, false, 0.0
+	return nil, false, 0.0, pathCount
 }
 
-func getNeighbors(current *PathPoint, cellSize, obstacleOffset float64, q worldQuery.WorldQuery) []*PathPoint {
+func getNeighbors(current *PathPoint, cellSize, obstacleOffset float64, q worldQuery.WorldQuery, who worldQuery.Passer) []*PathPoint {
 	neighbors := make([]*PathPoint, 0, 8)
 	directions := []struct{ dx, dz float64 }{
 		{cellSize, 0},
@@ -110,11 +117,11 @@ func getNeighbors(current *PathPoint, cellSize, obstacleOffset float64, q worldQ
 		newX := current.X + dir.dx
 		newZ := current.Z + dir.dz
 
-		if q.IsPointBlocked(newX, newZ) {
+		if q.IsPointBlocked(newX, newZ, who) {
 			continue
 		}
-		if !isPointValidWithOffset(newX, newZ, obstacleOffset, q) {
-			if !isPointValidWithOffset(newX, newZ, obstacleOffset*0.5, q) {
+		if !isPointValidWithOffset(newX, newZ, obstacleOffset, q, who) {
+			if !isPointValidWithOffset(newX, newZ, obstacleOffset*0.5, q, who) {
 				continue
 			}
 		}
@@ -123,8 +130,8 @@ func getNeighbors(current *PathPoint, cellSize, obstacleOffset float64, q worldQ
 	return neighbors
 }
 
-func isPointValidWithOffset(x, z, offset float64, q worldQuery.WorldQuery) bool {
-	if q.IsPointBlocked(x, z) {
+func isPointValidWithOffset(x, z, offset float64, q worldQuery.WorldQuery, who worldQuery.Passer) bool {
+	if q.IsPointBlocked(x, z, who) {
 		return false
 	}
 	const checkPoints = 8
@@ -132,7 +139,7 @@ func isPointValidWithOffset(x, z, offset float64, q worldQuery.WorldQuery) bool
 		angle := float64(i) * 2 * math.Pi / float64(checkPoints)
 		checkX := x + math.Cos(angle)*offset
 		checkZ := z + math.Sin(angle)*offset
-		if q.IsPointBlocked(checkX, checkZ) {
+		if q.IsPointBlocked(checkX, checkZ, who) {
 			return false
 		}
 	}
@@ -0,0 +1,71 @@
+package navgrid
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/events"
+	worldQuery "veatla/simulator/src/world-query"
+
+	"github.com/google/uuid"
+)
+
+// plain is an open 40x40 world whose ground costs cost(x, z) to cross.
+type plain struct {
+	cost func(x, z float64) float64
+}
+
+func (p plain) IsPointBlocked(x, z float64, _ worldQuery.Passer) bool {
+	return x < 0 || z < 0 || x >= 40 || z >= 40
+}
+func (p plain) RandomFloat() float64              { return rand.Float64() }
+func (p plain) NewID() uuid.UUID                  { return uuid.Nil }
+func (p plain) GetWorldSeed() int64               { return 1 }
+func (p plain) GetBoundaries() (float64, float64) { return 40, 40 }
+func (p plain) DayLength() time.Duration          { return calendar.DefaultDayLength }
+func (p plain) Now() calendar.Time                { return calendar.Time{} }
+func (p plain) MoveCost(x, z float64) float64     { return p.cost(x, z) }
+func (p plain) Emit(events.Event)                 {}
+
+func flat(c float64) plain { return plain{func(float64, float64) float64 { return c }} }
+
+func TestMoveCostScalesPathCost(t *testing.T) {
+	for _, c := range []float64{1, 2.5} {
+		path, ok, cost := AStarPath(1, 10, 21, 10, flat(c), worldQuery.Passer{}, 0)
+		if !ok {
+			t.Fatalf("cost %v: no path", c)
+		}
+		length := 0.0
+		for i := 1; i < len(path); i++ {
+			length += distance(path[i-1].X, path[i-1].Z, path[i].X, path[i].Z)
+		}
+		if d := cost - c*length; d > 1e-9 || d < -1e-9 {
+			t.Errorf("ground costing %v: path cost %v over length %v", c, cost, length)
+		}
+	}
+}
+
+func TestPathAvoidsCostlyGround(t *testing.T) {
+	// A bog between start and goal, ending at z = 16; walking around it is
+	// far cheaper than wading through.
+	bog := plain{func(x, z float64) float64 {
+		if x >= 6 && x < 16 && z < 16 {
+			return 20
+		}
+		return 1
+	}}
+	path, ok, cost := AStarPath(1, 10, 21, 10, bog, worldQuery.Passer{}, 0)
+	if !ok {
+		t.Fatal("no path")
+	}
+	for _, p := range path {
+		if bog.cost(p.X, p.Z) > 1 {
+			t.Fatalf("path steps into the bog at (%v, %v)", p.X, p.Z)
+		}
+	}
+	if cost >= 20*20 {
+		t.Fatalf("cost %v is no cheaper than crossing the bog", cost)
+	}
+}
@@ -7,6 +7,11 @@ type PathPoint struct {
 	X, Z float64
 }
 
+// SearchStats describes the work done by one A* search.
+type SearchStats struct {
+	Expansions int
+}
+
 // Pather is the interface for A* pathfinding.
 type Pather interface {
 	PathNeighbors(q worldQuery.WorldQuery) []Pather
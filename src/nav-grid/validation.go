@@ -5,13 +5,13 @@ import (
 	worldQuery "veatla/simulator/src/world-query"
 )
 
-func validatePathSimple(path []PathPoint, q worldQuery.WorldQuery, obstacleOffset, cellSize float64) bool {
+func validatePathSimple(path []PathPoint, q worldQuery.WorldQuery, who worldQuery.Passer, obstacleOffset, cellSize float64) bool {
 	if len(path) == 0 {
 		return false
 	}
 
 	for _, p := range path {
-		if q.IsPointBlocked(p.X, p.Z) {
+		if q.IsPointBlocked(p.X, p.Z, who) {
 			return false
 		}
 	}
@@ -25,7 +25,7 @@ func validatePathSimple(path []PathPoint, q worldQuery.WorldQuery, obstacleOffse
 			t := float64(s) / 3.0
 			sx := a.X + dx*t
 			sz := a.Z + dz*t
-			if q.IsPointBlocked(sx, sz) {
+			if q.IsPointBlocked(sx, sz, who) {
 				return false
 			}
 		}
@@ -33,14 +33,14 @@ func validatePathSimple(path []PathPoint, q worldQuery.WorldQuery, obstacleOffse
 	return true
 }
 
-func attemptRepair(path []PathPoint, idx int, q worldQuery.WorldQuery, obstacleOffset, cellSize float64) ([]PathPoint, bool) {
+func attemptRepair(path []PathPoint, idx int, q worldQuery.WorldQuery, who worldQuery.Passer, obstacleOffset, cellSize float64) ([]PathPoint, bool) {
 	if idx < 0 || idx >= len(path) {
 		return nil, false
 	}
 	start := path[idx]
 	goal := path[len(path)-1]
 
-	newPath, found, _ := aStarSearch(start.X, start.Z, goal.X, goal.Z, q, obstacleOffset, cellSize)
+	newPath, found, _, _ := aStarSearch(start.X, start.Z, goal.X, goal.Z, q, who, obstacleOffset, cellSize)
 	if !found || len(newPath) == 0 {
 		return nil, false
 	}
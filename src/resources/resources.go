@@ -0,0 +1,104 @@
+package resources
+
+import (
+	"errors"
+
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/goods"
+)
+
+var ErrUnknownKind = errors.New("unknown resource kind")
+
+// Kind is the type of a resource node.
+type Kind string
+
+const (
+	KindTree Kind = "tree"
+	KindRock Kind = "rock"
+	KindOre  Kind = "ore"
+)
+
+// Yield describes what working a node of one kind produces.
+type Yield struct {
+	Good goods.Good
+	// Skill is the agent skill that gathers the node.
+	Skill string
+	// WorkDays is the gatherer-days of labour per unit at a skill of 1.
+	WorkDays float64
+	// RegrowDays is how long a depleted node takes to grow back; zero means
+	// it is gone for good.
+	RegrowDays float64
+}
+
+// Kinds are the known node kinds.
+var Kinds = map[Kind]Yield{
+	KindTree: {Good: goods.Wood, Skill: "woodcutting", WorkDays: 0.02, RegrowDays: 6},
+	KindRock: {Good: goods.Stone, Skill: "mining", WorkDays: 0.03},
+	KindOre:  {Good: goods.Iron, Skill: "mining", WorkDays: 0.05},
+}
+
+// Gathers reports whether g is the yield of any node kind.
+func Gathers(g goods.Good) bool {
+	for _, y := range Kinds {
+		if y.Good == g {
+			return true
+		}
+	}
+	return false
+}
+
+// Node is a harvestable resource standing on the map. While it has anything
+// left it blocks movement like any other obstacle.
+type Node struct {
+	constructions.Obstacle
+	Kind   Kind
+	Amount int
+	// Full is the amount the node starts with and grows back to.
+	Full int
+	// Work is labour done towards the next unit, in units.
+	Work float64
+	// Regrow is the days spent depleted, counting towards RegrowDays.
+	Regrow float64
+}
+
+// New creates a node of the given kind covering o.
+func New(kind Kind, o constructions.Obstacle, amount int) (Node, error) {
+	if _, ok := Kinds[kind]; !ok {
+		return Node{}, ErrUnknownKind
+	}
+	return Node{Obstacle: o, Kind: kind, Amount: amount, Full: amount}, nil
+}
+
+// Yield returns what the node produces.
+func (n *Node) Yield() Yield { return Kinds[n.Kind] }
+
+// Depleted reports whether the node has nothing left.
+func (n *Node) Depleted() bool { return n.Amount <= 0 }
+
+// Take removes up to q units and returns how many were taken.
+func (n *Node) Take(q int) int {
+	q = min(q, n.Amount)
+	if q <= 0 {
+		return 0
+	}
+	n.Amount -= q
+	if n.Amount == 0 {
+		n.Work, n.Regrow = 0, 0
+	}
+	return q
+}
+
+// Grow advances a depleted node by days and reports whether it has grown
+// back to full.
+func (n *Node) Grow(days float64) bool {
+	regrow := n.Yield().RegrowDays
+	if !n.Depleted() || regrow <= 0 {
+		return false
+	}
+	n.Regrow += days
+	if n.Regrow < regrow {
+		return false
+	}
+	n.Amount, n.Regrow = n.Full, 0
+	return true
+}
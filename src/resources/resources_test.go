@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"errors"
+	"testing"
+
+	"veatla/simulator/src/constructions"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New("bush", constructions.Obstacle{}, 3); !errors.Is(err, ErrUnknownKind) {
+		t.Fatalf("err = %v, want %v", err, ErrUnknownKind)
+	}
+	n, err := New(KindOre, constructions.Obstacle{}, 3)
+	if err != nil || n.Amount != 3 || n.Full != 3 {
+		t.Fatalf("node = %+v, %v", n, err)
+	}
+}
+
+func TestTakeDepletes(t *testing.T) {
+	n, _ := New(KindRock, constructions.Obstacle{}, 3)
+	n.Work = 0.5
+	if got := n.Take(2); got != 2 || n.Depleted() {
+		t.Fatalf("took %d, depleted %v", got, n.Depleted())
+	}
+	if got := n.Take(5); got != 1 || !n.Depleted() || n.Work != 0 {
+		t.Fatalf("took %d, depleted %v, work %v", got, n.Depleted(), n.Work)
+	}
+	if got := n.Take(1); got != 0 {
+		t.Fatalf("took %d from a depleted node", got)
+	}
+}
+
+func TestGrow(t *testing.T) {
+	tree, _ := New(KindTree, constructions.Obstacle{}, 4)
+	if tree.Grow(100) {
+		t.Fatal("a standing tree grew")
+	}
+	tree.Take(4)
+	days := tree.Yield().RegrowDays
+	if tree.Grow(days / 2) {
+		t.Fatal("tree grew back in half the time")
+	}
+	if !tree.Grow(days/2) || tree.Amount != tree.Full {
+		t.Fatalf("tree not regrown: %+v", tree)
+	}
+
+	rock, _ := New(KindRock, constructions.Obstacle{}, 1)
+	rock.Take(1)
+	if rock.Grow(1000) {
+		t.Fatal("a rock grew back")
+	}
+}
@@ -0,0 +1,268 @@
+package scenario
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/generator"
+	"veatla/simulator/src/goods"
+	"veatla/simulator/src/ledger"
+	spatialhash "veatla/simulator/src/spatial-hash"
+	"veatla/simulator/src/world"
+	worldQuery "veatla/simulator/src/world-query"
+	"veatla/simulator/src/zones"
+)
+
+// Config describes the starting state of a simulation run.
+type Config struct {
+	Seed          int64
+	Width, Height float64
+	Agents        int
+	// Houses is the number of starting houses built inside the castle.
+	Houses int
+	// Stockpiles is the number of starting stockpiles, placed before the houses.
+	Stockpiles int
+	// Markets, Mints and Taverns are the number of starting markets, mints
+	// and taverns, placed outside the gate.
+	Markets int
+	Mints   int
+	Taverns int
+	// Farms is the number of starting farm fields, placed on farmland near
+	// the castle when there is some.
+	Farms int
+	// DayLength is the simulated length of a day; zero keeps the default.
+	DayLength time.Duration
+	// Treasury is the castle's starting money.
+	Treasury int64
+	// Archetypes overrides the built-in agent definitions when non-nil.
+	Archetypes agents.Archetypes
+}
+
+// Default returns the scenario used by the interactive server.
+func Default(seed int64) Config {
+	return Config{
+		Seed:       seed,
+		Width:      50,
+		Height:     50,
+		Agents:     1,
+		Houses:     3,
+		Stockpiles: 1,
+		Markets:    1,
+		Mints:      1,
+		Taverns:    1,
+		Farms:      2,
+		Treasury:   500,
+	}
+}
+
+// Build creates a world with a generated map and the initial agents.
+func Build(cfg Config) *world.World {
+	w := world.NewWorld(cfg.Seed, cfg.Width, cfg.Height)
+	if cfg.Archetypes != nil {
+		w.Archetypes = cfg.Archetypes
+	}
+	w.SetDayLength(cfg.DayLength)
+	w.GenerateMap()
+	w.Castle.Treasury = cfg.Treasury
+	w.Castle.Current.TreasuryStart = cfg.Treasury
+	w.Deposit(ledger.Treasury, cfg.Treasury, ledger.ReasonInitial)
+
+	inside := make([]constructions.BuildingKind, 0, cfg.Houses+cfg.Stockpiles)
+	for range cfg.Stockpiles {
+		inside = append(inside, constructions.BuildingStockpile)
+	}
+	for range cfg.Houses {
+		inside = append(inside, constructions.BuildingHouse)
+	}
+	placeBuildings(&w, inside)
+
+	outside := make([]constructions.BuildingKind, 0, cfg.Markets+cfg.Mints+cfg.Taverns)
+	for range cfg.Markets {
+		outside = append(outside, constructions.BuildingMarket)
+	}
+	for range cfg.Mints {
+		outside = append(outside, constructions.BuildingMint)
+	}
+	for range cfg.Taverns {
+		outside = append(outside, constructions.BuildingTavern)
+	}
+	placeOutside(&w, outside)
+	placeFields(&w, cfg.Farms)
+
+	for range cfg.Agents {
+		w.AddAgent(agents.CreateFromArchetype(&w, w.PickArchetype()))
+	}
+	return &w
+}
+
+const (
+	buildingSize  = 3
+	buildingGap   = 2
+	houseCapacity = 4
+	// workplaceCapacity is the number of workers at other buildings.
+	workplaceCapacity = 2
+	// outsideSearch is how far from the gate outside buildings may be placed.
+	outsideSearch = 12
+	marketMoney   = 200
+	fieldSize     = 4
+	// fieldSearch is how far from the gate farmland is worth farming.
+	fieldSearch = 20
+)
+
+// startingStock is what each starting stockpile holds.
+var startingStock = goods.Stock{
+	goods.Wood:  40,
+	goods.Stone: 20,
+	goods.Grain: 30,
+}
+
+// marketStock is what each starting market holds.
+var marketStock = goods.Stock{
+	goods.Bread: 40,
+	goods.Grain: 20,
+	goods.Tools: 5,
+	goods.Cloth: 10,
+}
+
+// newBuilding creates a starting building of the given kind at (x, z) with
+// its initial capacity, stock and money.
+func newBuilding(w *world.World, kind constructions.BuildingKind, x, z float64) constructions.Building {
+	capacity := workplaceCapacity
+	if kind == constructions.BuildingHouse {
+		capacity = houseCapacity
+	}
+	b := constructions.CreateBuilding(w.NewID(), kind, x, z, x+buildingSize, z+buildingSize, capacity)
+	switch kind {
+	case constructions.BuildingStockpile:
+		b.Stock = startingStock.Clone()
+	case constructions.BuildingMarket:
+		b.Stock = marketStock.Clone()
+		b.Money = marketMoney
+	}
+	return b
+}
+
+// placeBuildings lines up buildings of the given kinds along the inside of
+// the castle's north wall, wrapping to further rows as needed.
+func placeBuildings(w *world.World, kinds []constructions.BuildingKind) {
+	if w.Map == nil || len(kinds) == 0 {
+		return
+	}
+	c := w.Map.Castle
+	x0, z0 := c.MinX+buildingGap, c.MinZ+buildingGap
+	x, z := x0, z0
+	for _, kind := range kinds {
+		if x+buildingSize > c.MaxX-buildingGap {
+			x = x0
+			z += buildingSize + buildingGap
+		}
+		if z+buildingSize > c.MaxZ-buildingGap-buildingSize {
+			return
+		}
+		w.AddBuilding(newBuilding(w, kind, x, z))
+		x += buildingSize + buildingGap
+	}
+}
+
+// placeOutside puts buildings of the given kinds on free ground outside the
+// castle gate, searching outwards in rings.
+func placeOutside(w *world.World, kinds []constructions.BuildingKind) {
+	if w.Map == nil || len(kinds) == 0 {
+		return
+	}
+	g := w.Map.Castle.Gate
+	cx := math.Floor((g.MinX+g.MaxX)/2) - buildingSize/2
+	cz := math.Floor(g.MaxZ) + 1
+	for r := 0; r <= outsideSearch && len(kinds) > 0; r++ {
+		for dz := -r; dz <= r && len(kinds) > 0; dz++ {
+			for dx := -r; dx <= r && len(kinds) > 0; dx++ {
+				if max(abs(dx), abs(dz)) != r {
+					continue
+				}
+				x, z := cx+float64(dx), cz+float64(dz)
+				if !footprintFree(w, x, z, buildingSize) {
+					continue
+				}
+				w.AddBuilding(newBuilding(w, kinds[0], x, z))
+				kinds = kinds[1:]
+			}
+		}
+	}
+}
+
+// placeFields lays out n square farm fields, first on farmland deposits
+// near the gate, then on free ground outside it.
+func placeFields(w *world.World, n int) {
+	if w.Map == nil || n <= 0 {
+		return
+	}
+	g := w.Map.Castle.Gate
+	gx, gz := (g.MinX+g.MaxX)/2, g.MaxZ
+	var spots [][2]float64
+	for _, d := range w.Map.Deposits {
+		if d.Kind == generator.DepositFarmland && math.Hypot(d.X-gx, d.Z-gz) <= fieldSearch {
+			spots = append(spots, [2]float64{math.Floor(d.X) - fieldSize/2, math.Floor(d.Z) - fieldSize/2})
+		}
+	}
+	sort.SliceStable(spots, func(a, b int) bool {
+		return math.Hypot(spots[a][0]-gx, spots[a][1]-gz) < math.Hypot(spots[b][0]-gx, spots[b][1]-gz)
+	})
+	cx, cz := math.Floor(gx)-fieldSize/2, math.Floor(gz)+1
+	for r := 0; r <= outsideSearch; r++ {
+		for dz := -r; dz <= r; dz++ {
+			for dx := -r; dx <= r; dx++ {
+				if max(abs(dx), abs(dz)) == r {
+					spots = append(spots, [2]float64{cx + float64(dx), cz + float64(dz)})
+				}
+			}
+		}
+	}
+	for _, p := range spots {
+		if n == 0 {
+			return
+		}
+		if !footprintFree(w, p[0], p[1], fieldSize) || zoned(w, p[0], p[1], fieldSize) {
+			continue
+		}
+		z, err := zones.NewRect(w.NewID(), zones.KindFarm, p[0], p[1], p[0]+fieldSize, p[1]+fieldSize)
+		if err != nil {
+			continue
+		}
+		if _, err := w.DesignateZone(z); err == nil {
+			n--
+		}
+	}
+}
+
+// zoned reports whether a size x size square at (x, z), with a one tile
+// margin, overlaps a zone.
+func zoned(w *world.World, x, z, size float64) bool {
+	box := spatialhash.AABB{MinX: x - 1, MinZ: z - 1, MaxX: x + size + 1, MaxZ: z + size + 1}
+	return len(w.ZoneIndex.Overlapping(nil, box)) > 0
+}
+
+// footprintFree reports whether a size x size square at (x, z) is inside
+// the map and unblocked, with a one tile margin so it does not seal paths.
+func footprintFree(w *world.World, x, z, size float64) bool {
+	if x < 1 || z < 1 || x+size > w.Width-1 || z+size > w.Height-1 {
+		return false
+	}
+	for tz := z - 1; tz < z+size+1; tz++ {
+		for tx := x - 1; tx < x+size+1; tx++ {
+			if w.IsPointBlocked(tx+0.5, tz+0.5, worldQuery.Passer{}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
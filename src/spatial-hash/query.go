@@ -0,0 +1,303 @@
+package spatialhash
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Kind selects which entities a query looks at.
+type Kind uint8
+
+const (
+	KindAgent Kind = iota
+	KindObstacle
+)
+
+// AABB is an axis-aligned box in world coordinates.
+type AABB struct {
+	MinX, MinZ float64
+	MaxX, MaxZ float64
+}
+
+// Overlaps reports whether two boxes intersect (touching edges count).
+func (b AABB) Overlaps(o AABB) bool {
+	return b.MinX <= o.MaxX && b.MaxX >= o.MinX && b.MinZ <= o.MaxZ && b.MaxZ >= o.MinZ
+}
+
+// DistanceTo returns the distance from a point to the closest point of the box.
+func (b AABB) DistanceTo(x, z float64) float64 {
+	dx := math.Max(math.Max(b.MinX-x, 0), x-b.MaxX)
+	dz := math.Max(math.Max(b.MinZ-z, 0), z-b.MaxZ)
+	return math.Sqrt(dx*dx + dz*dz)
+}
+
+// Crosses reports where the segment (x0, z0)-(x1, z1) enters the box, as a
+// fraction t in [0, 1] of the segment.
+func (b AABB) Crosses(x0, z0, x1, z1 float64) (t float64, ok bool) {
+	return segmentAABB(x0, z0, x1-x0, z1-z0, b)
+}
+
+type entity struct {
+	box   AABB
+	kind  Kind
+	cells cellRange
+}
+
+// Neighbor is a k-nearest result.
+type Neighbor struct {
+	ID   uuid.UUID
+	Dist float64
+}
+
+// Bounds returns the inserted bounds of an entity.
+func (s *SpatialHash) Bounds(id uuid.UUID) (AABB, bool) {
+	e, ok := s.entities[id]
+	if !ok {
+		return AABB{}, false
+	}
+	return e.box, true
+}
+
+func (s *SpatialHash) cellIDs(cell *Cell, kind Kind) []uuid.UUID {
+	if kind == KindObstacle {
+		return cell.obstacles
+	}
+	return cell.agents
+}
+
+// forEachCell calls fn for every existing cell overlapping box, passing the
+// cell coordinates and the range walked.
+func (s *SpatialHash) forEachCell(box AABB, fn func(c *Cell, cx, cz int32, r cellRange)) {
+	r := s.rangeFor(box)
+	for cx := r.x0; cx <= r.x1; cx++ {
+		for cz := r.z0; cz <= r.z1; cz++ {
+			if c := s.cell(cx, cz, false); c != nil {
+				fn(c, cx, cz, r)
+			}
+		}
+	}
+}
+
+// firstCell reports whether (cx, cz) is the first cell of the walked range r
+// that the entity covers. An entity spanning several cells is reported only
+// from that cell, so queries need no per-query set and stay safe for
+// concurrent readers.
+func firstCell(e *entity, r cellRange, cx, cz int32) bool {
+	return cx == max(e.cells.x0, r.x0) && cz == max(e.cells.z0, r.z0)
+}
+
+// QueryRadius appends to dst the IDs of entities of the given kind whose
+// bounds lie within r of (x, z). Each entity is reported once.
+func (s *SpatialHash) QueryRadius(dst []uuid.UUID, x, z, r float64, kind Kind) []uuid.UUID {
+	box := AABB{MinX: x - r, MinZ: z - r, MaxX: x + r, MaxZ: z + r}
+	s.forEachCell(box, func(c *Cell, cx, cz int32, walked cellRange) {
+		for _, id := range s.cellIDs(c, kind) {
+			e, ok := s.entities[id]
+			if !ok || !firstCell(e, walked, cx, cz) || e.box.DistanceTo(x, z) > r {
+				continue
+			}
+			dst = append(dst, id)
+		}
+	})
+	return dst
+}
+
+// QueryAABB appends to dst the IDs of entities of the given kind overlapping
+// box. Each entity is reported once.
+func (s *SpatialHash) QueryAABB(dst []uuid.UUID, box AABB, kind Kind) []uuid.UUID {
+	s.forEachCell(box, func(c *Cell, cx, cz int32, walked cellRange) {
+		for _, id := range s.cellIDs(c, kind) {
+			e, ok := s.entities[id]
+			if !ok || !firstCell(e, walked, cx, cz) || !e.box.Overlaps(box) {
+				continue
+			}
+			dst = append(dst, id)
+		}
+	})
+	return dst
+}
+
+// KNearest returns up to k entities of the given kind nearest to (x, z),
+// sorted by exact distance and no farther than maxRadius. Results are written
+// into dst[:0]; pass a slice with capacity k to avoid allocating.
+func (s *SpatialHash) KNearest(dst []Neighbor, x, z float64, k int, kind Kind, maxRadius float64) []Neighbor {
+	dst = dst[:0]
+	if k <= 0 {
+		return dst
+	}
+
+	cs := float64(s.CellSize)
+	cx, cz := s.cellFor(float32(x), float32(z))
+	maxRing := int32(math.Ceil(maxRadius/cs)) + 1
+
+	for ring := int32(0); ring <= maxRing; ring++ {
+		// Anything in this ring or beyond is at least this far away.
+		if len(dst) == k && float64(ring-1)*cs > dst[k-1].Dist {
+			break
+		}
+		s.forEachRingCell(cx, cz, ring, func(c *Cell) {
+			for _, id := range s.cellIDs(c, kind) {
+				e, ok := s.entities[id]
+				if !ok {
+					continue
+				}
+				d := e.box.DistanceTo(x, z)
+				if d > maxRadius {
+					continue
+				}
+				dst = insertNeighbor(dst, Neighbor{ID: id, Dist: d}, k)
+			}
+		})
+	}
+	return dst
+}
+
+func (s *SpatialHash) forEachRingCell(cx, cz, ring int32, fn func(c *Cell)) {
+	visit := func(x, z int32) {
+		if c := s.cell(x, z, false); c != nil {
+			fn(c)
+		}
+	}
+	if ring == 0 {
+		visit(cx, cz)
+		return
+	}
+	for dx := -ring; dx <= ring; dx++ {
+		visit(cx+dx, cz-ring)
+		visit(cx+dx, cz+ring)
+	}
+	for dz := -ring + 1; dz <= ring-1; dz++ {
+		visit(cx-ring, cz+dz)
+		visit(cx+ring, cz+dz)
+	}
+}
+
+// insertNeighbor keeps dst sorted by distance, unique by ID and at most k long.
+func insertNeighbor(dst []Neighbor, n Neighbor, k int) []Neighbor {
+	for _, v := range dst {
+		if v.ID == n.ID {
+			return dst
+		}
+	}
+	if len(dst) == k && n.Dist >= dst[k-1].Dist {
+		return dst
+	}
+	if len(dst) < k {
+		dst = append(dst, n)
+	} else {
+		dst[k-1] = n
+	}
+	for i := len(dst) - 1; i > 0 && dst[i].Dist < dst[i-1].Dist; i-- {
+		dst[i], dst[i-1] = dst[i-1], dst[i]
+	}
+	return dst
+}
+
+// Raycast walks the cells crossed by the segment (x0, z0)-(x1, z1) and returns
+// the first obstacle it hits and the hit position as a fraction t in [0, 1]
+// of the segment.
+func (s *SpatialHash) Raycast(x0, z0, x1, z1 float64) (id uuid.UUID, t float64, hit bool) {
+	cs := float64(s.CellSize)
+	dx, dz := x1-x0, z1-z0
+	cx, cz := s.cellFor(float32(x0), float32(z0))
+	ex, ez := s.cellFor(float32(x1), float32(z1))
+
+	stepX, stepZ := int32(1), int32(1)
+	if dx < 0 {
+		stepX = -1
+	}
+	if dz < 0 {
+		stepZ = -1
+	}
+	tMaxX, tDeltaX := dda(x0, dx, cx, stepX, cs)
+	tMaxZ, tDeltaZ := dda(z0, dz, cz, stepZ, cs)
+
+	// Bound the walk by the Manhattan cell distance in case rounding makes the
+	// traversal miss the end cell.
+	steps := absInt32(ex-cx) + absInt32(ez-cz) + 1
+	best := math.Inf(1)
+	for ; steps >= 0; steps-- {
+		if c := s.cell(cx, cz, false); c != nil {
+			for _, oid := range c.obstacles {
+				e, ok := s.entities[oid]
+				if !ok {
+					continue
+				}
+				if ht, ok := segmentAABB(x0, z0, dx, dz, e.box); ok && ht < best {
+					best, id = ht, oid
+				}
+			}
+		}
+		// A hit inside the cells walked so far cannot be beaten by later cells.
+		if best <= math.Min(tMaxX, tMaxZ) || (cx == ex && cz == ez) {
+			break
+		}
+		if tMaxX < tMaxZ {
+			cx += stepX
+			tMaxX += tDeltaX
+		} else {
+			cz += stepZ
+			tMaxZ += tDeltaZ
+		}
+	}
+
+	if math.IsInf(best, 1) {
+		return uuid.UUID{}, 0, false
+	}
+	return id, best, true
+}
+
+// LineOfSight reports whether no obstacle blocks the segment between two points.
+func (s *SpatialHash) LineOfSight(x0, z0, x1, z1 float64) bool {
+	_, _, hit := s.Raycast(x0, z0, x1, z1)
+	return !hit
+}
+
+func absInt32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// dda returns the segment parameter of the first cell boundary crossed along
+// one axis and the parameter step between boundaries.
+func dda(origin, delta float64, cell, step int32, cs float64) (tMax, tDelta float64) {
+	if delta == 0 {
+		return math.Inf(1), math.Inf(1)
+	}
+	boundary := float64(cell) * cs
+	if step > 0 {
+		boundary += cs
+	}
+	return (boundary - origin) / delta, cs / math.Abs(delta)
+}
+
+// segmentAABB intersects the segment p + t*d, t in [0, 1], with a box using
+// the slab method and returns the entry parameter.
+func segmentAABB(px, pz, dx, dz float64, b AABB) (float64, bool) {
+	tMin, tMax := 0.0, 1.0
+	for _, axis := range [2]struct{ p, d, lo, hi float64 }{
+		{px, dx, b.MinX, b.MaxX},
+		{pz, dz, b.MinZ, b.MaxZ},
+	} {
+		if axis.d == 0 {
+			if axis.p < axis.lo || axis.p > axis.hi {
+				return 0, false
+			}
+			continue
+		}
+		t1 := (axis.lo - axis.p) / axis.d
+		t2 := (axis.hi - axis.p) / axis.d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = math.Max(tMin, t1)
+		tMax = math.Min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
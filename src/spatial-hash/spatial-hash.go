@@ -1,14 +1,61 @@
 package spatialhash
 
-import "github.com/google/uuid"
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
 
 type Cell struct {
 	agents    []uuid.UUID
 	obstacles []uuid.UUID
 }
+
+func (c *Cell) empty() bool {
+	return len(c.agents) == 0 && len(c.obstacles) == 0
+}
+
+// SpatialHash buckets entity IDs into square cells. When the world bounds are
+// known (NewBounded) in-bounds cells live in a dense slice; everything else
+// falls back to the sparse Cells map, whose empty cells are evicted.
 type SpatialHash struct {
 	CellSize float32
 	Cells    map[int64]*Cell
+
+	dense      []Cell
+	cols, rows int32
+
+	// entities holds the bounds and covered cell range of every entity so
+	// updates only touch the cells that changed.
+	entities map[uuid.UUID]*entity
+}
+
+// cellRange is the inclusive range of cells covered by an entity.
+type cellRange struct {
+	x0, z0, x1, z1 int32
+}
+
+func (r cellRange) contains(cx, cz int32) bool {
+	return cx >= r.x0 && cx <= r.x1 && cz >= r.z0 && cz <= r.z1
+}
+
+// New creates an empty sparse spatial hash with the given cell size.
+func New(cellSize float32) SpatialHash {
+	return SpatialHash{
+		CellSize: cellSize,
+		Cells:    make(map[int64]*Cell),
+		entities: make(map[uuid.UUID]*entity),
+	}
+}
+
+// NewBounded creates a spatial hash with a dense cell array covering
+// [0, width] x [0, height].
+func NewBounded(cellSize float32, width, height float64) SpatialHash {
+	s := New(cellSize)
+	s.cols = int32(math.Floor(width/float64(cellSize))) + 1
+	s.rows = int32(math.Floor(height/float64(cellSize))) + 1
+	s.dense = make([]Cell, int(s.cols)*int(s.rows))
+	return s
 }
 
 func HashCell(x, z int32) int64 {
@@ -16,40 +63,157 @@ func HashCell(x, z int32) int64 {
 }
 
 func (s *SpatialHash) cellFor(x, z float32) (int32, int32) {
-	return int32(x / s.CellSize), int32(z / s.CellSize)
+	return int32(math.Floor(float64(x / s.CellSize))), int32(math.Floor(float64(z / s.CellSize)))
 }
-func (s *SpatialHash) Clear(includeStructures bool) {
-	for _, c := range s.Cells {
-		c.agents = c.agents[:0]
 
-		if includeStructures == true {
-			c.obstacles = c.obstacles[:0]
+func (s *SpatialHash) rangeFor(b AABB) cellRange {
+	x0, z0 := s.cellFor(float32(b.MinX), float32(b.MinZ))
+	x1, z1 := s.cellFor(float32(b.MaxX), float32(b.MaxZ))
+	return cellRange{x0: x0, z0: z0, x1: x1, z1: z1}
+}
+
+// cell returns the cell at (cx, cz), creating it when create is set.
+func (s *SpatialHash) cell(cx, cz int32, create bool) *Cell {
+	if cx >= 0 && cz >= 0 && cx < s.cols && cz < s.rows {
+		return &s.dense[cz*s.cols+cx]
+	}
+	key := HashCell(cx, cz)
+	c, ok := s.Cells[key]
+	if !ok && create {
+		c = &Cell{}
+		s.Cells[key] = c
+	}
+	return c
+}
+
+// evict drops a sparse cell once it holds nothing.
+func (s *SpatialHash) evict(cx, cz int32) {
+	if cx >= 0 && cz >= 0 && cx < s.cols && cz < s.rows {
+		return
+	}
+	key := HashCell(cx, cz)
+	if c, ok := s.Cells[key]; ok && c.empty() {
+		delete(s.Cells, key)
+	}
+}
+
+// Clear removes every agent, and obstacles too when includeStructures is set.
+func (s *SpatialHash) Clear(includeStructures bool) {
+	for id, e := range s.entities {
+		if e.kind == KindAgent || includeStructures {
+			s.Remove(id)
 		}
 	}
 }
 
+// Insert adds an entity covering [x, x2] x [z, z2]. Inserting an ID that is
+// already present moves it instead.
 func (s *SpatialHash) Insert(id uuid.UUID, x, z float64, x2, z2 float64, structure bool) {
-	for dx := x; dx <= x2; dx += float64(s.CellSize) {
-		for dz := z; dz <= z2; dz += float64(s.CellSize) {
-			s.insertAtCell(id, dx, dz, structure)
+	box := AABB{MinX: x, MinZ: z, MaxX: x2, MaxZ: z2}
+	if e, ok := s.entities[id]; ok {
+		s.Move(id, e.box, box)
+		return
+	}
+
+	kind := KindAgent
+	if structure {
+		kind = KindObstacle
+	}
+	r := s.rangeFor(box)
+	if s.entities == nil {
+		s.entities = make(map[uuid.UUID]*entity)
+	}
+	s.entities[id] = &entity{box: box, kind: kind, cells: r}
+
+	for cx := r.x0; cx <= r.x1; cx++ {
+		for cz := r.z0; cz <= r.z1; cz++ {
+			s.addToCell(s.cell(cx, cz, true), id, kind)
 		}
 	}
 }
 
-func (s *SpatialHash) insertAtCell(id uuid.UUID, x, z float64, structure bool) {
-	cx, cz := s.cellFor(float32(x), float32(z))
-	key := HashCell(cx, cz)
+// Move updates an entity's bounds from oldBox to newBox, touching only the
+// cells it leaves or enters. The stored cell membership is authoritative, so
+// a stale oldBox cannot leave the entity behind in a cell. Unknown IDs are
+// ignored.
+func (s *SpatialHash) Move(id uuid.UUID, oldBox, newBox AABB) {
+	e, ok := s.entities[id]
+	if !ok {
+		return
+	}
+	oldRange := e.cells
+	newRange := s.rangeFor(newBox)
+	e.box = newBox
+	if newRange == oldRange {
+		return
+	}
+	e.cells = newRange
+
+	for cx := oldRange.x0; cx <= oldRange.x1; cx++ {
+		for cz := oldRange.z0; cz <= oldRange.z1; cz++ {
+			if newRange.contains(cx, cz) {
+				continue
+			}
+			if c := s.cell(cx, cz, false); c != nil {
+				s.removeFromCell(c, id, e.kind)
+				s.evict(cx, cz)
+			}
+		}
+	}
+	for cx := newRange.x0; cx <= newRange.x1; cx++ {
+		for cz := newRange.z0; cz <= newRange.z1; cz++ {
+			if !oldRange.contains(cx, cz) {
+				s.addToCell(s.cell(cx, cz, true), id, e.kind)
+			}
+		}
+	}
+}
 
-	cell, ok := s.Cells[key]
+// Remove deletes an entity from every cell it occupies.
+func (s *SpatialHash) Remove(id uuid.UUID) {
+	e, ok := s.entities[id]
 	if !ok {
-		cell = &Cell{}
-		s.Cells[key] = cell
+		return
+	}
+	delete(s.entities, id)
+
+	r := e.cells
+	for cx := r.x0; cx <= r.x1; cx++ {
+		for cz := r.z0; cz <= r.z1; cz++ {
+			if c := s.cell(cx, cz, false); c != nil {
+				s.removeFromCell(c, id, e.kind)
+				s.evict(cx, cz)
+			}
+		}
 	}
+}
+
+// Len returns the number of entities in the hash.
+func (s *SpatialHash) Len() int {
+	return len(s.entities)
+}
 
-	if structure == false {
-		cell.agents = append(cell.agents, id)
+func (s *SpatialHash) addToCell(c *Cell, id uuid.UUID, kind Kind) {
+	if kind == KindObstacle {
+		c.obstacles = append(c.obstacles, id)
 	} else {
-		cell.obstacles = append(cell.obstacles, id)
+		c.agents = append(c.agents, id)
+	}
+}
+
+func (s *SpatialHash) removeFromCell(c *Cell, id uuid.UUID, kind Kind) {
+	list := &c.agents
+	if kind == KindObstacle {
+		list = &c.obstacles
+	}
+	ids := *list
+	for i, v := range ids {
+		if v == id {
+			last := len(ids) - 1
+			ids[i] = ids[last]
+			*list = ids[:last]
+			return
+		}
 	}
 }
 
@@ -60,8 +224,7 @@ func (s *SpatialHash) Nearby(x, z float32, structure bool) []uuid.UUID {
 
 	for dx := -1; dx <= 1; dx++ {
 		for dz := -1; dz <= 1; dz++ {
-			key := HashCell(cx+int32(dx), cz+int32(dz))
-			if cell := s.Cells[key]; cell != nil {
+			if cell := s.cell(cx+int32(dx), cz+int32(dz), false); cell != nil {
 				if structure == false {
 					result = append(result, cell.agents...)
 				} else {
@@ -76,10 +239,9 @@ func (s *SpatialHash) Nearby(x, z float32, structure bool) []uuid.UUID {
 
 func (s *SpatialHash) IsPointBlocked(x, z float64) bool {
 	cx, cz := s.cellFor(float32(x), float32(z))
-	key := HashCell(cx, cz)
 
-	cell, ok := s.Cells[key]
-	if !ok {
+	cell := s.cell(cx, cz, false)
+	if cell == nil {
 		return false
 	}
 
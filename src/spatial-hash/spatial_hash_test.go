@@ -0,0 +1,260 @@
+package spatialhash
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func id(n byte) uuid.UUID { return uuid.UUID{15: n} }
+
+func sorted(ids []uuid.UUID) []uuid.UUID {
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+	return ids
+}
+
+func TestBoundsUnknown(t *testing.T) {
+	s := NewBounded(1, 10, 10)
+	if _, ok := s.Bounds(id(1)); ok {
+		t.Fatal("unknown ID reported bounds")
+	}
+	s.Insert(id(1), 1, 1, 2, 2, false)
+	s.Remove(id(1))
+	if _, ok := s.Bounds(id(1)); ok {
+		t.Fatal("removed ID reported bounds")
+	}
+}
+
+func TestInsertMoveRemove(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		s    SpatialHash
+	}{
+		{"dense", NewBounded(1, 10, 10)},
+		{"sparse", New(1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.s
+			s.Insert(id(1), 1, 1, 2, 2, false)
+			s.Insert(id(2), 5, 5, 8, 8, true)
+			if s.Len() != 2 {
+				t.Fatalf("Len = %d, want 2", s.Len())
+			}
+			if !s.IsPointBlocked(6, 6) || s.IsPointBlocked(1.5, 1.5) {
+				t.Fatal("only the structure should block")
+			}
+
+			s.Move(id(1), AABB{1, 1, 2, 2}, AABB{3, 3, 4, 4})
+			if got := s.QueryRadius(nil, 1.5, 1.5, 0.5, KindAgent); len(got) != 0 {
+				t.Fatalf("agent left behind in old cells: %v", got)
+			}
+			if got := s.QueryRadius(nil, 3.5, 3.5, 0.5, KindAgent); !slices.Equal(got, []uuid.UUID{id(1)}) {
+				t.Fatalf("moved agent not found: %v", got)
+			}
+			if b, ok := s.Bounds(id(1)); !ok || b != (AABB{3, 3, 4, 4}) {
+				t.Fatalf("Bounds = %v, %v", b, ok)
+			}
+
+			// A stale old box must not strand the entity.
+			s.Move(id(1), AABB{0, 0, 0, 0}, AABB{7, 1, 7.5, 1.5})
+			if got := s.QueryRadius(nil, 3.5, 3.5, 0.5, KindAgent); len(got) != 0 {
+				t.Fatalf("stale move left agent behind: %v", got)
+			}
+
+			s.Move(id(9), AABB{}, AABB{1, 1, 2, 2})
+			s.Remove(id(9))
+			s.Remove(id(2))
+			if s.IsPointBlocked(6, 6) || s.Len() != 1 {
+				t.Fatal("removed structure still present")
+			}
+		})
+	}
+}
+
+func TestQueriesReportOnce(t *testing.T) {
+	s := NewBounded(1, 20, 20)
+	// Spans many cells, so every query walks it more than once.
+	s.Insert(id(1), 2, 2, 9, 9, true)
+	s.Insert(id(2), 12, 12, 13, 13, true)
+	s.Insert(id(3), 4, 4, 5, 5, false)
+
+	for _, tc := range []struct {
+		name string
+		got  []uuid.UUID
+		want []uuid.UUID
+	}{
+		{"radius around", s.QueryRadius(nil, 5, 5, 3, KindObstacle), []uuid.UUID{id(1)}},
+		{"radius both", s.QueryRadius(nil, 10.5, 10.5, 3, KindObstacle), []uuid.UUID{id(1), id(2)}},
+		{"radius agents", s.QueryRadius(nil, 5, 5, 3, KindAgent), []uuid.UUID{id(3)}},
+		{"radius miss", s.QueryRadius(nil, 18, 2, 1, KindObstacle), nil},
+		{"aabb inside", s.QueryAABB(nil, AABB{5, 5, 6, 6}, KindObstacle), []uuid.UUID{id(1)}},
+		{"aabb all", s.QueryAABB(nil, AABB{0, 0, 20, 20}, KindObstacle), []uuid.UUID{id(1), id(2)}},
+		{"appends", s.QueryAABB([]uuid.UUID{id(7)}, AABB{12, 12, 12.5, 12.5}, KindObstacle), []uuid.UUID{id(7), id(2)}},
+	} {
+		if !slices.Equal(sorted(tc.got), sorted(tc.want)) {
+			t.Errorf("%s: got %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestKNearest(t *testing.T) {
+	s := NewBounded(1, 20, 20)
+	s.Insert(id(1), 1, 1, 1.5, 1.5, false)
+	s.Insert(id(2), 4, 1, 4.5, 1.5, false)
+	s.Insert(id(3), 10, 1, 10.5, 1.5, false)
+
+	got := s.KNearest(nil, 0, 1, 2, KindAgent, 20)
+	if len(got) != 2 || got[0].ID != id(1) || got[1].ID != id(2) {
+		t.Fatalf("KNearest = %v", got)
+	}
+	if got := s.KNearest(nil, 0, 1, 5, KindAgent, 2); len(got) != 1 {
+		t.Fatalf("maxRadius not applied: %v", got)
+	}
+}
+
+func TestRaycast(t *testing.T) {
+	s := NewBounded(1, 20, 20)
+	s.Insert(id(1), 5, 0, 6, 10, true)
+	s.Insert(id(2), 8, 0, 9, 10, true)
+
+	hit, at, ok := s.Raycast(1, 5, 15, 5)
+	if !ok || hit != id(1) || at <= 0 || at >= 1 {
+		t.Fatalf("Raycast = %v, %v, %v", hit, at, ok)
+	}
+	if s.LineOfSight(1, 5, 15, 5) {
+		t.Fatal("wall did not block line of sight")
+	}
+	if !s.LineOfSight(1, 12, 15, 12) {
+		t.Fatal("clear line blocked")
+	}
+}
+
+// benchCounts are the entity counts each benchmark runs at.
+var benchCounts = []int{1_000, 10_000, 100_000}
+
+// benchStep is how far each agent moves per tick and benchRadius how far
+// each agent queries around itself.
+const (
+	benchStep   = 0.04
+	benchRadius = 4
+)
+
+type benchAgent struct {
+	id     uuid.UUID
+	x, z   float64
+	vx, vz float64
+}
+
+// benchWorld spreads n agents over a square world at roughly 0.4 agents per
+// cell.
+func benchWorld(n int) ([]benchAgent, float64) {
+	side := math.Ceil(math.Sqrt(float64(n) / 0.4))
+	rng := rand.New(rand.NewSource(1))
+	agents := make([]benchAgent, n)
+	for i := range agents {
+		angle := rng.Float64() * 2 * math.Pi
+		agents[i] = benchAgent{
+			id: uuid.New(),
+			x:  rng.Float64() * (side - 1),
+			z:  rng.Float64() * (side - 1),
+			vx: math.Cos(angle),
+			vz: math.Sin(angle),
+		}
+	}
+	return agents, side
+}
+
+func (a *benchAgent) step(d, side float64) {
+	a.x += a.vx * d
+	a.z += a.vz * d
+	if a.x < 0 || a.x > side-1 {
+		a.vx = -a.vx
+		a.x = math.Min(math.Max(a.x, 0), side-1)
+	}
+	if a.z < 0 || a.z > side-1 {
+		a.vz = -a.vz
+		a.z = math.Min(math.Max(a.z, 0), side-1)
+	}
+}
+
+func (a *benchAgent) box() AABB {
+	return AABB{MinX: a.x, MinZ: a.z, MaxX: a.x + 1, MaxZ: a.z + 1}
+}
+
+func fill(grid *SpatialHash, agents []benchAgent) {
+	for i := range agents {
+		box := agents[i].box()
+		grid.Insert(agents[i].id, box.MinX, box.MinZ, box.MaxX, box.MaxZ, false)
+	}
+}
+
+// BenchmarkInsert measures clearing the grid and re-inserting every agent,
+// the per-tick rebuild that Move replaces.
+func BenchmarkInsert(b *testing.B) {
+	for _, n := range benchCounts {
+		b.Run(fmt.Sprintf("agents=%d", n), func(b *testing.B) {
+			agents, side := benchWorld(n)
+			grid := New(1)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				grid.Clear(false)
+				for i := range agents {
+					agents[i].step(benchStep, side)
+				}
+				fill(&grid, agents)
+			}
+		})
+	}
+}
+
+// BenchmarkMove measures one tick of incremental updates on dense and sparse
+// grids.
+func BenchmarkMove(b *testing.B) {
+	for _, dense := range []bool{true, false} {
+		for _, n := range benchCounts {
+			b.Run(fmt.Sprintf("dense=%v/agents=%d", dense, n), func(b *testing.B) {
+				agents, side := benchWorld(n)
+				grid := New(1)
+				if dense {
+					grid = NewBounded(1, side, side)
+				}
+				fill(&grid, agents)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for range b.N {
+					for i := range agents {
+						a := &agents[i]
+						old := a.box()
+						a.step(benchStep, side)
+						grid.Move(a.id, old, a.box())
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkQueryRadius measures one radius query per agent per tick.
+func BenchmarkQueryRadius(b *testing.B) {
+	for _, n := range benchCounts {
+		b.Run(fmt.Sprintf("agents=%d", n), func(b *testing.B) {
+			agents, side := benchWorld(n)
+			grid := NewBounded(1, side, side)
+			fill(&grid, agents)
+			var buf []uuid.UUID
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				for i := range agents {
+					a := &agents[i]
+					buf = grid.QueryRadius(buf[:0], a.x, a.z, benchRadius, KindAgent)
+				}
+			}
+		})
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 // FindOffsetPosition finds a valid position offset from obstacles
-func FindOffsetPosition(x, z, offsetDistance float64, q worldQuery.WorldQuery) (float64, float64, bool) {
+func FindOffsetPosition(x, z, offsetDistance float64, q worldQuery.WorldQuery, who worldQuery.Passer) (float64, float64, bool) {
 	const numAngles = 16
 	const step = 0.1
 
@@ -19,7 +19,7 @@ func FindOffsetPosition(x, z, offsetDistance float64, q worldQuery.WorldQuery) (
 			offsetX := x + math.Cos(angle)*currentDist
 			offsetZ := z + math.Sin(angle)*currentDist
 
-			if !q.IsPointBlocked(offsetX, offsetZ) && isOffsetValid(offsetX, offsetZ, offsetDistance, q) {
+			if !q.IsPointBlocked(offsetX, offsetZ, who) && isOffsetValid(offsetX, offsetZ, offsetDistance, q, who) {
 				return offsetX, offsetZ, true
 			}
 		}
@@ -29,8 +29,8 @@ func FindOffsetPosition(x, z, offsetDistance float64, q worldQuery.WorldQuery) (
 }
 
 // isOffsetValid checks if a position is valid and maintains offset from obstacles
-func isOffsetValid(x, z, offsetDistance float64, q worldQuery.WorldQuery) bool {
-	if q.IsPointBlocked(x, z) {
+func isOffsetValid(x, z, offsetDistance float64, q worldQuery.WorldQuery, who worldQuery.Passer) bool {
+	if q.IsPointBlocked(x, z, who) {
 		return false
 	}
 
@@ -41,7 +41,7 @@ func isOffsetValid(x, z, offsetDistance float64, q worldQuery.WorldQuery) bool {
 		checkX := x + math.Cos(angle)*offsetDistance
 		checkZ := z + math.Sin(angle)*offsetDistance
 
-		if q.IsPointBlocked(checkX, checkZ) {
+		if q.IsPointBlocked(checkX, checkZ, who) {
 			return false
 		}
 	}
@@ -50,7 +50,7 @@ func isOffsetValid(x, z, offsetDistance float64, q worldQuery.WorldQuery) bool {
 }
 
 // GetSafeWanderingTarget tries to find a safe wandering target with offset from obstacles
-func GetSafeWanderingTarget(currentX, currentZ, maxRadius, offsetDistance float64, q worldQuery.WorldQuery, rng interface {
+func GetSafeWanderingTarget(currentX, currentZ, maxRadius, offsetDistance float64, q worldQuery.WorldQuery, who worldQuery.Passer, rng interface {
 	Float64() float64
 	Intn(n int) int
 }) (float64, float64, bool) {
@@ -73,8 +73,8 @@ func GetSafeWanderingTarget(currentX, currentZ, maxRadius, offsetDistance float6
 		}
 
 		// Find offset position
-		if !q.IsPointBlocked(targetX, targetZ) {
-			if offsetX, offsetZ, ok := FindOffsetPosition(targetX, targetZ, offsetDistance, q); ok {
+		if !q.IsPointBlocked(targetX, targetZ, who) {
+			if offsetX, offsetZ, ok := FindOffsetPosition(targetX, targetZ, offsetDistance, q, who); ok {
 				return offsetX, offsetZ, true
 			}
 		}
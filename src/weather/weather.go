@@ -0,0 +1,123 @@
+package weather
+
+import (
+	"math"
+	"math/rand"
+
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/utils"
+)
+
+// Kind is the sky over the map.
+type Kind string
+
+const (
+	Clear Kind = "clear"
+	Rain  Kind = "rain"
+	Snow  Kind = "snow"
+	Storm Kind = "storm"
+)
+
+// changeChance is the chance each hour that the weather turns.
+const changeChance = 0.12
+
+// odds are the relative chances of each kind when the weather turns, by
+// season.
+var odds = [4][]struct {
+	kind   Kind
+	weight float64
+}{
+	calendar.Spring: {{Clear, 5}, {Rain, 4}, {Storm, 1}},
+	calendar.Summer: {{Clear, 7}, {Rain, 2}, {Storm, 1}},
+	calendar.Autumn: {{Clear, 4}, {Rain, 4.5}, {Storm, 1.5}},
+	calendar.Winter: {{Clear, 4.5}, {Snow, 4}, {Storm, 1.5}},
+}
+
+// effect is how a kind of weather changes the ground and slows work.
+type effect struct {
+	// wet and snow are how fast the ground soaks and snow settles, per day.
+	// Negative values dry the ground and melt snow.
+	wet, snow float64
+	// speed scales how fast agents walk; work scales outdoor work; growth
+	// scales how fast crops grow.
+	speed, work, growth float64
+}
+
+var effects = map[Kind]effect{
+	Clear: {wet: -2, snow: -1, speed: 1, work: 1, growth: 1},
+	Rain:  {wet: 6, snow: -2, speed: 0.9, work: 0.8, growth: 1.2},
+	Snow:  {wet: -0.5, snow: 4, speed: 0.8, work: 0.7, growth: 0.5},
+	Storm: {wet: 10, snow: 1, speed: 0.7, work: 0.4, growth: 0.8},
+}
+
+// State is the current weather and what it has left on the ground.
+type State struct {
+	Kind Kind `json:"kind"`
+	// Wetness is how sodden the ground is, from 0 (dry) to 1.
+	Wetness float64 `json:"wetness"`
+	// Cover is how deep the snow lies, from 0 to 1.
+	Cover float64 `json:"cover"`
+}
+
+// Turn may change the weather at the top of an hour in season s, drawing
+// from rng. It reports whether the kind changed.
+func (s *State) Turn(rng *rand.Rand, season calendar.Season) bool {
+	if rng.Float64() >= changeChance {
+		return false
+	}
+	opts := odds[season]
+	total := 0.0
+	for _, o := range opts {
+		total += o.weight
+	}
+	r := rng.Float64() * total
+	next := opts[len(opts)-1].kind
+	for _, o := range opts {
+		if r < o.weight {
+			next = o.kind
+			break
+		}
+		r -= o.weight
+	}
+	if next == s.Kind {
+		return false
+	}
+	s.Kind = next
+	return true
+}
+
+// Settle soaks or dries the ground and settles or melts snow over days of
+// simulated time. Snow only lies in winter.
+func (s *State) Settle(season calendar.Season, days float64) {
+	e := s.effect()
+	s.Wetness = utils.Clamp(s.Wetness+e.wet*days, 0, 1)
+	snow := e.snow
+	if season != calendar.Winter {
+		snow = -math.Abs(snow)
+	}
+	before := s.Cover
+	s.Cover = utils.Clamp(s.Cover+snow*days, 0, 1)
+	// melting snow soaks the ground
+	if before > s.Cover {
+		s.Wetness = utils.Clamp(s.Wetness+before-s.Cover, 0, 1)
+	}
+}
+
+// MoveCost is how many times longer it takes to cross ground of the given
+// muddiness (0 for rock, 1 for bare earth) than on a dry, clear day.
+func (s *State) MoveCost(muddiness float64) float64 {
+	return (1 + s.Wetness*muddiness*0.8 + s.Cover*0.5) / s.effect().speed
+}
+
+// Work scales the progress of work done outdoors.
+func (s *State) Work() float64 { return s.effect().work }
+
+// Growth scales how fast crops grow.
+func (s *State) Growth() float64 { return s.effect().growth }
+
+func (s *State) effect() effect {
+	if e, ok := effects[s.Kind]; ok {
+		return e
+	}
+	return effects[Clear]
+}
@@ -0,0 +1,81 @@
+package weather
+
+import (
+	"math/rand"
+	"testing"
+
+	"veatla/simulator/src/calendar"
+)
+
+func TestMoveCost(t *testing.T) {
+	clear := State{Kind: Clear}
+	if c := clear.MoveCost(1); c != 1 {
+		t.Fatalf("dry clear day costs %v, want 1", c)
+	}
+	muddy := State{Kind: Clear, Wetness: 1}
+	if muddy.MoveCost(1) <= muddy.MoveCost(0.3) || muddy.MoveCost(0.3) <= clear.MoveCost(0.3) {
+		t.Errorf("mud: earth %v, hill %v, dry hill %v", muddy.MoveCost(1), muddy.MoveCost(0.3), clear.MoveCost(0.3))
+	}
+	prev := clear.MoveCost(1)
+	for _, k := range []Kind{Rain, Snow, Storm} {
+		s := State{Kind: k}
+		if c := s.MoveCost(1); c <= prev {
+			t.Errorf("%s costs %v, want more than %v", k, c, prev)
+		} else {
+			prev = c
+		}
+	}
+}
+
+func TestSettle(t *testing.T) {
+	s := State{Kind: Rain}
+	s.Settle(calendar.Spring, 1)
+	if s.Wetness != 1 || s.Cover != 0 {
+		t.Fatalf("after a day of rain: %+v", s)
+	}
+	s.Kind = Clear
+	s.Settle(calendar.Spring, 0.25)
+	if s.Wetness != 0.5 {
+		t.Fatalf("wetness %v after drying, want 0.5", s.Wetness)
+	}
+
+	snow := State{Kind: Snow}
+	snow.Settle(calendar.Autumn, 1)
+	if snow.Cover != 0 {
+		t.Fatalf("snow settled in autumn: %+v", snow)
+	}
+	snow.Settle(calendar.Winter, 0.25)
+	if snow.Cover != 1 {
+		t.Fatalf("cover %v after winter snow, want 1", snow.Cover)
+	}
+	// Thawing snow soaks the ground.
+	snow.Kind = Clear
+	snow.Settle(calendar.Spring, 1)
+	if snow.Cover != 0 || snow.Wetness == 0 {
+		t.Fatalf("after the thaw: %+v", snow)
+	}
+}
+
+func TestTurnFollowsSeason(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := State{Kind: Clear}
+	seen := map[Kind]bool{}
+	for range 2000 {
+		s.Turn(rng, calendar.Summer)
+		seen[s.Kind] = true
+	}
+	if seen[Snow] {
+		t.Error("snow in summer")
+	}
+	if !seen[Rain] || !seen[Storm] {
+		t.Errorf("summer weather seen: %v", seen)
+	}
+
+	a, b := State{}, State{}
+	ra, rb := rand.New(rand.NewSource(2)), rand.New(rand.NewSource(2))
+	for range 500 {
+		if a.Turn(ra, calendar.Winter) != b.Turn(rb, calendar.Winter) || a != b {
+			t.Fatal("same seed turned differently")
+		}
+	}
+}
@@ -1,8 +1,34 @@
 package worldQuery
 
+import (
+	"time"
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/events"
+
+	"github.com/google/uuid"
+)
+
+// Passer identifies who is asking whether a point is blocked: closed gates
+// still let their own factions through. The zero Passer is a stranger.
+type Passer struct {
+	ID      uuid.UUID
+	Faction string
+}
+
 type WorldQuery interface {
-	IsPointBlocked(x, z float64) bool
+	IsPointBlocked(x, z float64, who Passer) bool
 	RandomFloat() float64
+	// NewID returns an ID drawn from the world RNG so seeded runs are reproducible.
+	NewID() uuid.UUID
 	GetWorldSeed() int64
 	GetBoundaries() (width, height float64)
+	// DayLength is the simulated duration of one in-world day.
+	DayLength() time.Duration
+	// Now is the current calendar time.
+	Now() calendar.Time
+	// MoveCost is how many times longer it takes to cross (x, z) than dry
+	// ground on a clear day; it is never below 1.
+	MoveCost(x, z float64) float64
+	// Emit publishes a simulation event on the world's event bus.
+	Emit(e events.Event)
 }
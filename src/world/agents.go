@@ -0,0 +1,129 @@
+package world
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/ledger"
+	navgrid "veatla/simulator/src/nav-grid"
+	spatialhash "veatla/simulator/src/spatial-hash"
+
+	"github.com/google/uuid"
+)
+
+// agentBox is the footprint an agent occupies in the grid.
+func agentBox(a *agents.Agent) spatialhash.AABB {
+	return spatialhash.AABB{MinX: a.X, MinZ: a.Z, MaxX: a.X + a.Width, MaxZ: a.Z + a.Height}
+}
+
+// AddAgent places an initial agent in the world.
+func (w *World) AddAgent(a agents.Agent) {
+	w.SpawnAgent(a, events.ReasonInitial)
+}
+
+// SpawnAgent places an agent in the world, registers it in the grid, gives
+// it a lifespan if it has none and lets it look around.
+func (w *World) SpawnAgent(a agents.Agent, reason string) {
+	if a.Lifespan == 0 {
+		a.Lifespan = w.Population.lifespan(w.rng)
+	}
+	w.Agents = append(w.Agents, a)
+	reindex(&w.agentSlot, w.Agents, len(w.Agents)-1, agentID)
+	w.Deposit(a.ID, a.Money, reason)
+	box := agentBox(&a)
+	w.Grid.Insert(a.ID, box.MinX, box.MinZ, box.MaxX, box.MaxZ, false)
+	added := &w.Agents[len(w.Agents)-1]
+	w.look(added)
+	w.remember(added, nil)
+	w.Emit(events.AgentSpawned{AgentID: a.ID, X: a.X, Z: a.Z, Reason: reason})
+}
+
+// RemoveAgent takes an agent out of the world and the grid. It must not be
+// called while agents are ticking.
+func (w *World) RemoveAgent(id uuid.UUID, reason string) bool {
+	i := w.agentIndex(id)
+	if i < 0 {
+		return false
+	}
+	w.leaveHousehold(&w.Agents[i], reason)
+	switch reason {
+	case events.ReasonDied:
+		w.witnessDeath(&w.Agents[i])
+		w.DropInventory(id)
+		w.Transfer(id, ledger.Treasury, w.Agents[i].Money, ledger.ReasonInheritance)
+	default:
+		w.withdraw(id, w.Agents[i].Money, ledger.ReasonEmigrated)
+	}
+	w.forgive(id)
+	delete(w.jobs, id)
+	delete(w.fieldJobs, id)
+	delete(w.gatherJobs, id)
+	a := w.Agents[i]
+	w.departed = addStats(w.departed, a.Stats())
+	w.Grid.Remove(id)
+	w.Agents = slices.Delete(w.Agents, i, i+1)
+	delete(w.agentSlot, id)
+	reindex(&w.agentSlot, w.Agents, i, agentID)
+	w.Emit(events.AgentDespawned{AgentID: a.ID, X: a.X, Z: a.Z, Reason: reason})
+	return true
+}
+
+func (w *World) agentIndex(id uuid.UUID) int {
+	return slotOf(w.agentSlot, id)
+}
+
+func agentID(a *agents.Agent) uuid.UUID { return a.ID }
+
+// PickArchetype draws an archetype by spawn weight from the world RNG.
+func (w *World) PickArchetype() agents.Archetype {
+	return w.Archetypes.Pick(w.rng.Float64())
+}
+
+// pathSample is the spacing at which paths are checked against an area.
+const pathSample = 0.25
+
+// pathEnters reports whether walking from (x, z) along path passes through a
+// point for which inside is true.
+func pathEnters(x, z float64, path []navgrid.PathPoint, inside func(x, z float64) bool) bool {
+	for _, p := range path {
+		dist := math.Hypot(p.X-x, p.Z-z)
+		steps := int(dist/pathSample) + 1
+		for s := 1; s <= steps; s++ {
+			t := float64(s) / float64(steps)
+			if inside(x+(p.X-x)*t, z+(p.Z-z)*t) {
+				return true
+			}
+		}
+		x, z = p.X, p.Z
+	}
+	return false
+}
+
+// eject moves an agent standing in an area it may not be in, such as a
+// closing gate or a new restricted zone, just past the nearest edge of box
+// that it can stand on.
+func (w *World) eject(a *agents.Agent, box spatialhash.AABB) {
+	exits := [][2]float64{
+		{a.X, box.MinZ - pathSample},
+		{a.X, box.MaxZ + pathSample},
+		{box.MinX - pathSample, a.Z},
+		{box.MaxX + pathSample, a.Z},
+	}
+	slices.SortStableFunc(exits, func(p, q [2]float64) int {
+		return cmp.Compare(math.Hypot(p[0]-a.X, p[1]-a.Z), math.Hypot(q[0]-a.X, q[1]-a.Z))
+	})
+	exit := exits[0]
+	for _, p := range exits {
+		if !w.IsPointBlocked(p[0], p[1], a.Passer()) {
+			exit = p
+			break
+		}
+	}
+	before := agentBox(a)
+	a.X, a.Z = exit[0], exit[1]
+	a.VX, a.VZ = 0, 0
+	w.Grid.Move(a.ID, before, agentBox(a))
+}
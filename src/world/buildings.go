@@ -0,0 +1,42 @@
+package world
+
+import (
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/ledger"
+	"veatla/simulator/src/market"
+
+	"github.com/google/uuid"
+)
+
+// AddBuilding appends a building and inserts its footprint into the grid.
+func (w *World) AddBuilding(b constructions.Building) {
+	w.Buildings = append(w.Buildings, b)
+	reindex(&w.buildingSlot, w.Buildings, len(w.Buildings)-1, buildingID)
+	w.Deposit(b.ID, b.Money, ledger.ReasonInitial)
+	w.Grid.Insert(b.ID, b.MinX, b.MinZ, b.MaxX, b.MaxZ, true)
+	if b.Kind == constructions.BuildingMarket {
+		w.Markets = append(w.Markets, market.New(b.ID))
+	}
+	w.Emit(events.BuildingPlaced{
+		BuildingID:   b.ID,
+		BuildingKind: string(b.Kind),
+		MinX:         b.MinX,
+		MinZ:         b.MinZ,
+		MaxX:         b.MaxX,
+		MaxZ:         b.MaxZ,
+	})
+}
+
+func buildingID(b *constructions.Building) uuid.UUID { return b.ID }
+
+// HousingCapacity returns how many agents the world's houses can hold.
+func (w *World) HousingCapacity() int {
+	total := 0
+	for _, b := range w.Buildings {
+		if b.Kind == constructions.BuildingHouse {
+			total += b.Capacity
+		}
+	}
+	return total
+}
@@ -0,0 +1,380 @@
+package world
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/goods"
+	spatialhash "veatla/simulator/src/spatial-hash"
+	"veatla/simulator/src/zones"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSiteNotFound = errors.New("construction site not found")
+	ErrSiteBlocked  = errors.New("construction site footprint is blocked")
+)
+
+// jobStep is what a construction worker is currently doing.
+type jobStep int
+
+const (
+	stepFetch   jobStep = iota // walking to a stockpile for materials
+	stepDeliver                // carrying materials to the site
+	stepWork                   // building at the site
+	stepWait                   // backing off after a failed job
+	stepReturn                 // taking materials a site no longer needs to a stockpile
+)
+
+// jobRetry is how many ticks a worker waits after failing to reach a job.
+const jobRetry = 200
+
+// job ties a builder or hauler to a site.
+type job struct {
+	site   uuid.UUID
+	step   jobStep
+	source uuid.UUID
+	good   goods.Good
+	until  int
+}
+
+// PlaceSite lays out a construction site with its minimum corner at (x, z).
+// The footprint must be inside the map and clear of obstacles, buildings and
+// other sites.
+func (w *World) PlaceSite(kind constructions.BuildingKind, x, z float64, priority int) (uuid.UUID, error) {
+	s, err := constructions.CreateSite(w.NewID(), kind, x, z)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	s.Priority = priority
+	box := siteBox(&s)
+	if box.MinX < 0 || box.MinZ < 0 || box.MaxX > w.Width || box.MaxZ > w.Height {
+		return uuid.Nil, ErrSiteBlocked
+	}
+	if len(w.Grid.QueryAABB(nil, box, spatialhash.KindObstacle)) > 0 || w.onFortification(box) {
+		return uuid.Nil, ErrSiteBlocked
+	}
+	for i := range w.Sites {
+		if siteBox(&w.Sites[i]).Overlaps(box) {
+			return uuid.Nil, ErrSiteBlocked
+		}
+	}
+	w.Sites = append(w.Sites, s)
+	w.Emit(events.SitePlaced{SiteID: s.ID, BuildingKind: string(kind), MinX: s.MinX, MinZ: s.MinZ, MaxX: s.MaxX, MaxZ: s.MaxZ, Priority: priority})
+	return s.ID, nil
+}
+
+// CancelSite removes a site. Delivered materials are left on the ground and
+// workers drop whatever they were carrying for it.
+func (w *World) CancelSite(id uuid.UUID) error {
+	i := w.siteIndex(id)
+	if i < 0 {
+		return ErrSiteNotFound
+	}
+	s := w.Sites[i]
+	if s.Scaffolded {
+		w.Grid.Remove(s.ID)
+	}
+	x, z, ok := w.doorPoint(s.Obstacle)
+	if !ok {
+		x, z = (s.MinX+s.MaxX)/2, (s.MinZ+s.MaxZ)/2
+	}
+	w.dropStock(s.Delivered, x, z)
+	w.Sites = append(w.Sites[:i], w.Sites[i+1:]...)
+	w.releaseWorkers(id, true)
+	w.Emit(events.SiteCancelled{SiteID: id})
+	return nil
+}
+
+// PrioritiseSite changes the order in which builders pick sites.
+func (w *World) PrioritiseSite(id uuid.UUID, priority int) error {
+	i := w.siteIndex(id)
+	if i < 0 {
+		return ErrSiteNotFound
+	}
+	w.Sites[i].Priority = priority
+	return nil
+}
+
+func siteBox(s *constructions.Site) spatialhash.AABB {
+	return spatialhash.AABB{MinX: s.MinX, MinZ: s.MinZ, MaxX: s.MaxX, MaxZ: s.MaxZ}
+}
+
+func (w *World) siteIndex(id uuid.UUID) int {
+	for i := range w.Sites {
+		if w.Sites[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// dropStock leaves goods on the ground at (x, z), one item per good.
+func (w *World) dropStock(stock goods.Stock, x, z float64) {
+	for _, g := range stock.Goods() {
+		it := goods.Item{ID: w.NewID(), X: x, Z: z, Good: g, Qty: stock[g]}
+		w.Items = append(w.Items, it)
+		w.Emit(events.ItemDropped{ItemID: it.ID, Good: string(g), Qty: it.Qty, X: x, Z: z})
+	}
+}
+
+// releaseWorkers ends every job at a site. Interrupted workers drop what
+// they carry; the others take materials on their way back to a stockpile.
+func (w *World) releaseWorkers(site uuid.UUID, interrupted bool) {
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		j, ok := w.jobs[a.ID]
+		if !ok || j.site != site || j.step == stepReturn {
+			continue
+		}
+		if !interrupted && j.step == stepDeliver {
+			w.returnLeftover(a, j.good)
+			continue
+		}
+		delete(w.jobs, a.ID)
+		if interrupted && !a.Inventory.Empty() {
+			w.Interrupt(a.ID)
+		}
+		a.ClearGoal()
+	}
+}
+
+// returnLeftover has a worker carry what a site no longer needs of g to the
+// nearest stockpile it knows of, or drop it where it stands if it knows
+// none, so the goods do not stay stranded in its inventory.
+func (w *World) returnLeftover(a *agents.Agent, g goods.Good) {
+	n := a.Inventory.Count(g)
+	if n == 0 {
+		delete(w.jobs, a.ID)
+		a.ClearGoal()
+		return
+	}
+	if store, ok := w.nearestStore(a); ok {
+		j := &job{step: stepReturn, source: store, good: g}
+		w.jobs[a.ID] = j
+		w.startStep(a, j)
+		return
+	}
+	w.dropStock(goods.Stock{g: a.Inventory.Take(g, n)}, a.X, a.Z)
+	delete(w.jobs, a.ID)
+	a.ClearGoal()
+}
+
+// isConstructionWorker reports whether an agent takes construction jobs.
+func isConstructionWorker(a *agents.Agent) bool {
+	return a.Behaviour == agents.BehaviourBuild || a.Behaviour == agents.BehaviourHaul
+}
+
+// sitesByPriority returns site indices, highest priority first and oldest
+// first among equals.
+func (w *World) sitesByPriority() []int {
+	order := make([]int, len(w.Sites))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return w.Sites[order[a]].Priority > w.Sites[order[b]].Priority
+	})
+	return order
+}
+
+// constructionTick advances every construction worker's job and finishes
+// sites whose work is done.
+func (w *World) constructionTick(dt time.Duration) {
+	if len(w.Sites) == 0 {
+		return
+	}
+	if w.jobs == nil {
+		w.jobs = map[uuid.UUID]*job{}
+	}
+	order := w.sitesByPriority()
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if !isConstructionWorker(a) {
+			continue
+		}
+		j, ok := w.jobs[a.ID]
+		if ok && j.step == stepWait && w.Ticks >= j.until {
+			delete(w.jobs, a.ID)
+			ok = false
+		}
+		if !ok {
+			if !w.onDuty(a) {
+				continue
+			}
+			j = w.findJob(a, order)
+			if j == nil {
+				continue
+			}
+			w.jobs[a.ID] = j
+			w.startStep(a, j)
+			continue
+		}
+		w.advanceJob(a, j, dt)
+	}
+
+	for i := 0; i < len(w.Sites); {
+		if w.finishSite(i) {
+			continue
+		}
+		i++
+	}
+}
+
+// findJob picks the first site, by priority, that the agent knows of and can
+// help with: haulers and builders fetch missing materials, builders also do
+// the work.
+func (w *World) findJob(a *agents.Agent, order []int) *job {
+	for _, si := range order {
+		s := &w.Sites[si]
+		if s.Complete() || !a.Memory.Knows(s.ID) {
+			continue
+		}
+		if g, _, missing := s.Missing(); missing {
+			if a.Inventory.Count(g) > 0 {
+				return &job{site: s.ID, step: stepDeliver, good: g}
+			}
+			if src, ok := w.stockpileWith(a, g); ok {
+				return &job{site: s.ID, step: stepFetch, source: src, good: g}
+			}
+			continue
+		}
+		if a.Behaviour == agents.BehaviourBuild {
+			return &job{site: s.ID, step: stepWork}
+		}
+	}
+	return nil
+}
+
+// stockpileWith returns the first stockpile or storage zone the agent knows
+// of holding g.
+func (w *World) stockpileWith(a *agents.Agent, g goods.Good) (uuid.UUID, bool) {
+	for i := range w.Buildings {
+		b := &w.Buildings[i]
+		if b.Kind == constructions.BuildingStockpile && b.Stock.Count(g) > 0 && a.Memory.Knows(b.ID) {
+			return b.ID, true
+		}
+	}
+	for i := range w.Zones {
+		z := &w.Zones[i]
+		if z.Kind == zones.KindStorage && z.Stock.Count(g) > 0 && a.Memory.Knows(z.ID) {
+			return z.ID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// startStep sends the agent to where its current step happens.
+func (w *World) startStep(a *agents.Agent, j *job) {
+	var x, z float64
+	ok := false
+	if j.step == stepFetch || j.step == stepReturn {
+		x, z, ok = w.storeApproach(j.source)
+	} else if si := w.siteIndex(j.site); si >= 0 {
+		x, z, ok = w.doorPoint(w.Sites[si].Obstacle)
+	}
+	if !ok {
+		delete(w.jobs, a.ID)
+		return
+	}
+	a.SetGoal(w, x, z)
+	if a.GoalFailed() {
+		w.jobs[a.ID] = &job{step: stepWait, until: w.Ticks + jobRetry}
+	}
+}
+
+func (w *World) advanceJob(a *agents.Agent, j *job, dt time.Duration) {
+	if j.step == stepWait {
+		return
+	}
+	si := w.siteIndex(j.site)
+	if si < 0 && j.step != stepReturn {
+		delete(w.jobs, a.ID)
+		a.ClearGoal()
+		return
+	}
+	if a.GoalFailed() {
+		w.jobs[a.ID] = &job{step: stepWait, until: w.Ticks + jobRetry}
+		return
+	}
+	if !a.AtGoal() {
+		return
+	}
+	if j.step == stepReturn {
+		if n := a.Inventory.Count(j.good); n > 0 {
+			if _, err := w.DropOff(a.ID, j.source, j.good, n); err != nil {
+				w.dropStock(goods.Stock{j.good: a.Inventory.Take(j.good, n)}, a.X, a.Z)
+			}
+		}
+		delete(w.jobs, a.ID)
+		a.ClearGoal()
+		return
+	}
+	s := &w.Sites[si]
+
+	switch j.step {
+	case stepFetch:
+		want := s.Needs(j.good)
+		if want > 0 {
+			w.PickUp(a.ID, j.source, j.good, want)
+		}
+		if a.Inventory.Count(j.good) == 0 {
+			delete(w.jobs, a.ID)
+			a.ClearGoal()
+			return
+		}
+		j.step = stepDeliver
+		w.startStep(a, j)
+
+	case stepDeliver:
+		n := a.Inventory.Take(j.good, s.Needs(j.good))
+		if n > 0 {
+			s.Delivered.Add(j.good, n)
+			w.Emit(events.GoodsDelivered{AgentID: a.ID, BuildingID: s.ID, Good: string(j.good), Qty: n})
+		}
+		w.returnLeftover(a, j.good)
+
+	case stepWork:
+		if !s.Supplied() || s.Complete() {
+			delete(w.jobs, a.ID)
+			a.ClearGoal()
+			return
+		}
+		skill := a.Skills["building"] * w.Weather.Work()
+		s.Progress = min(s.Progress+skill*float64(dt)/(s.Blueprint.Work*float64(w.Calendar.DayLength)), 1)
+		if s.ShouldScaffold() && w.footprintClear(siteBox(s)) {
+			s.Scaffolded = true
+			w.Grid.Insert(s.ID, s.MinX, s.MinZ, s.MaxX, s.MaxZ, true)
+		}
+	}
+}
+
+// footprintClear reports whether no agent stands in box.
+func (w *World) footprintClear(box spatialhash.AABB) bool {
+	return len(w.Grid.QueryAABB(nil, box, spatialhash.KindAgent)) == 0
+}
+
+// finishSite turns a complete site into a building once nobody stands in
+// it, and reports whether the site was removed.
+func (w *World) finishSite(i int) bool {
+	s := w.Sites[i]
+	if !s.Complete() {
+		return false
+	}
+	if !s.Scaffolded && !w.footprintClear(siteBox(&s)) {
+		return false
+	}
+	if s.Scaffolded {
+		w.Grid.Remove(s.ID)
+	}
+	w.Sites = append(w.Sites[:i], w.Sites[i+1:]...)
+	w.releaseWorkers(s.ID, false)
+	w.AddBuilding(s.Building())
+	w.Emit(events.SiteCompleted{SiteID: s.ID, BuildingKind: string(s.Blueprint.Kind)})
+	return true
+}
@@ -0,0 +1,172 @@
+package world
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/goods"
+
+	"github.com/google/uuid"
+)
+
+// builderWorld is an empty map with one stockpile at its west edge, one
+// house site and one agent standing at the site.
+func builderWorld(t *testing.T) (*World, *agents.Agent, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	w := NewWorld(1, 40, 40)
+	store := constructions.CreateBuilding(w.NewID(), constructions.BuildingStockpile, 2, 2, 5, 5, 2)
+	w.AddBuilding(store)
+	site, err := w.PlaceSite(constructions.BuildingHouse, 20, 20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddAgent(agents.CreateFromArchetypeAt(&w, mustArchetype(t, w.Archetypes), 21.5, 24))
+	a := &w.Agents[0]
+	a.Memory.See(store.ID, agents.PlaceStore, 3.5, 3.5)
+	w.jobs = map[uuid.UUID]*job{}
+	return &w, a, store.ID, site
+}
+
+func mustArchetype(t *testing.T, defs agents.Archetypes) agents.Archetype {
+	t.Helper()
+	def, ok := defs.Get(agents.DefaultArchetype)
+	if !ok {
+		t.Fatal("no default archetype")
+	}
+	return def
+}
+
+// arrive puts the agent at (x, z) with that as its goal.
+func arrive(w *World, a *agents.Agent, x, z float64) {
+	a.X, a.Z = x, z
+	a.SetGoal(w, x, z)
+}
+
+func TestDeliverLeftoverGoesBackToStore(t *testing.T) {
+	w, a, store, site := builderWorld(t)
+	s := &w.Sites[w.siteIndex(site)]
+	a.Inventory.Add(goods.Wood, 5)
+	// Another hauler fills the site while this one is on its way.
+	s.Delivered.Add(goods.Wood, s.Needs(goods.Wood))
+
+	j := &job{site: site, step: stepDeliver, good: goods.Wood}
+	w.jobs[a.ID] = j
+	arrive(w, a, a.X, a.Z)
+	w.advanceJob(a, j, 50*time.Millisecond)
+
+	j, ok := w.jobs[a.ID]
+	if !ok || j.step != stepReturn || j.source != store {
+		t.Fatalf("job after delivery = %+v, want a return to the stockpile", j)
+	}
+	x, z, _ := w.storeApproach(store)
+	arrive(w, a, x, z)
+	w.advanceJob(a, j, 50*time.Millisecond)
+
+	if n := a.Inventory.Count(goods.Wood); n != 0 {
+		t.Errorf("agent still carries %d wood", n)
+	}
+	if n := w.Buildings[w.buildingIndex(store)].Stock.Count(goods.Wood); n != 5 {
+		t.Errorf("stockpile holds %d wood, want 5", n)
+	}
+	if _, ok := w.jobs[a.ID]; ok {
+		t.Error("job not finished")
+	}
+}
+
+func TestDeliverLeftoverDroppedWithoutStore(t *testing.T) {
+	w, a, store, site := builderWorld(t)
+	a.Memory.Forget(store)
+	s := &w.Sites[w.siteIndex(site)]
+	a.Inventory.Add(goods.Wood, 5)
+	s.Delivered.Add(goods.Wood, s.Needs(goods.Wood)-2)
+
+	j := &job{site: site, step: stepDeliver, good: goods.Wood}
+	w.jobs[a.ID] = j
+	arrive(w, a, a.X, a.Z)
+	w.advanceJob(a, j, 50*time.Millisecond)
+
+	if s.Needs(goods.Wood) != 0 || a.Inventory.Count(goods.Wood) != 0 {
+		t.Fatalf("site needs %d, agent carries %d", s.Needs(goods.Wood), a.Inventory.Count(goods.Wood))
+	}
+	if len(w.Items) != 1 || w.Items[0].Good != goods.Wood || w.Items[0].Qty != 3 {
+		t.Fatalf("items on the ground = %+v, want 3 wood", w.Items)
+	}
+}
+
+func TestFindJobFollowsPriority(t *testing.T) {
+	w, a, store, first := builderWorld(t)
+	a.Behaviour = agents.BehaviourBuild
+	w.Buildings[w.buildingIndex(store)].Stock = goods.Stock{goods.Wood: 50, goods.Stone: 50}
+	second, err := w.PlaceSite(constructions.BuildingStockpile, 30, 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []uuid.UUID{first, second} {
+		s := &w.Sites[w.siteIndex(id)]
+		a.Memory.See(id, agents.PlaceSite, s.MinX, s.MinZ)
+	}
+
+	pick := func() *job { return w.findJob(a, w.sitesByPriority()) }
+	if j := pick(); j == nil || j.site != second || j.step != stepFetch || j.source != store || j.good != goods.Wood {
+		t.Fatalf("job = %+v, want wood fetched for the urgent site", j)
+	}
+	if err := w.PrioritiseSite(first, 9); err != nil {
+		t.Fatal(err)
+	}
+	if j := pick(); j == nil || j.site != first {
+		t.Fatalf("job = %+v, want the reprioritised site", j)
+	}
+	// Sites the agent has not seen are not its concern.
+	a.Memory.Forget(first)
+	if j := pick(); j == nil || j.site != second {
+		t.Fatalf("job = %+v, want the only known site", j)
+	}
+	// Once supplied, builders build.
+	s := &w.Sites[w.siteIndex(second)]
+	s.Delivered = s.Blueprint.Materials.Clone()
+	if j := pick(); j == nil || j.site != second || j.step != stepWork {
+		t.Fatalf("job = %+v, want work on the supplied site", j)
+	}
+	if err := w.PrioritiseSite(w.NewID(), 1); !errors.Is(err, ErrSiteNotFound) {
+		t.Errorf("unknown site: err = %v, want %v", err, ErrSiteNotFound)
+	}
+}
+
+func TestCancelSite(t *testing.T) {
+	w, a, _, site := builderWorld(t)
+	var cancelled bool
+	w.Events.Subscribe(func(r events.Record) {
+		if e, ok := r.Event.(events.SiteCancelled); ok && e.SiteID == site {
+			cancelled = true
+		}
+	})
+	s := &w.Sites[w.siteIndex(site)]
+	s.Delivered.Add(goods.Stone, 3)
+	a.Inventory.Add(goods.Wood, 2)
+	w.jobs[a.ID] = &job{site: site, step: stepDeliver, good: goods.Wood}
+
+	if err := w.CancelSite(site); err != nil {
+		t.Fatal(err)
+	}
+	if w.siteIndex(site) >= 0 || !cancelled {
+		t.Fatalf("site still there or no event: %v", cancelled)
+	}
+	if _, ok := w.jobs[a.ID]; ok || !a.Inventory.Empty() {
+		t.Fatal("worker kept its job or its load")
+	}
+	// What was delivered and what was on its way both lie on the ground.
+	on := goods.Stock{}
+	for _, it := range w.Items {
+		on.Add(it.Good, it.Qty)
+	}
+	if on.Count(goods.Stone) != 3 || on.Count(goods.Wood) != 2 {
+		t.Fatalf("on the ground: %v", on)
+	}
+	if err := w.CancelSite(site); !errors.Is(err, ErrSiteNotFound) {
+		t.Errorf("cancelling twice: err = %v, want %v", err, ErrSiteNotFound)
+	}
+}
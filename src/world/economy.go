@@ -0,0 +1,396 @@
+package world
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/castle"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/goods"
+	"veatla/simulator/src/ledger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ledgerHistory is the number of transfers kept for per-account queries.
+	ledgerHistory = 1 << 16
+	// fiscalHistory is the number of daily fiscal reports kept.
+	fiscalHistory = 365
+)
+
+var (
+	ErrUnknownAccount    = errors.New("unknown account")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrNotAMint          = errors.New("building is not a mint")
+)
+
+// EconomyRules controls wages, rent and minting. Amounts are in coins.
+type EconomyRules struct {
+	// ShiftLength is how long a work shift lasts; wages are paid at its end.
+	ShiftLength time.Duration
+	// Wage is paid to each worker per shift.
+	Wage int64
+	// Rent is collected from each agent per day by the treasury.
+	Rent int64
+	// MintPerWorker is the coins a mint creates per worker per shift.
+	MintPerWorker int64
+	// LuxuryAbove is the savings above which agents buy luxury goods.
+	LuxuryAbove int64
+	// Allowance is the money household members keep each day; the rest goes
+	// into the shared budget, which tops up members short of it.
+	Allowance int64
+	// TaxComfort is the combined tax rate agents accept without complaint.
+	// Each day, happiness changes by TaxMood times how far the policy's
+	// burden is below (or above) it.
+	TaxComfort float64
+	TaxMood    float64
+}
+
+func DefaultEconomyRules() EconomyRules {
+	return EconomyRules{
+		ShiftLength:   calendar.DefaultDayLength / 3,
+		Wage:          4,
+		Rent:          2,
+		MintPerWorker: 10,
+		LuxuryAbove:   60,
+		Allowance:     20,
+		TaxComfort:    0.25,
+		TaxMood:       0.5,
+	}
+}
+
+// balance returns a pointer to the money held by an agent, a building, a
+// household or the treasury.
+func (w *World) balance(id uuid.UUID) *int64 {
+	if id == ledger.Treasury {
+		return &w.Castle.Treasury
+	}
+	if i := w.agentIndex(id); i >= 0 {
+		return &w.Agents[i].Money
+	}
+	if i := w.buildingIndex(id); i >= 0 {
+		return &w.Buildings[i].Money
+	}
+	if i := w.householdIndex(id); i >= 0 {
+		return &w.Households[i].Money
+	}
+	return nil
+}
+
+// reindex records the index of every element of s from from on in slot,
+// creating it if needed. It runs after appending to or deleting from s.
+func reindex[T any](slot *map[uuid.UUID]int, s []T, from int, id func(*T) uuid.UUID) {
+	if *slot == nil {
+		*slot = make(map[uuid.UUID]int, len(s))
+	}
+	for i := from; i < len(s); i++ {
+		(*slot)[id(&s[i])] = i
+	}
+}
+
+// slotOf looks id up in a map kept by reindex, returning -1 if it is absent.
+func slotOf(slot map[uuid.UUID]int, id uuid.UUID) int {
+	if i, ok := slot[id]; ok {
+		return i
+	}
+	return -1
+}
+
+// Transfer moves coins between two accounts inside the world and records
+// it in the ledger.
+func (w *World) Transfer(from, to uuid.UUID, amount int64, reason string) error {
+	if amount <= 0 {
+		return nil
+	}
+	src, dst := w.balance(from), w.balance(to)
+	if src == nil || dst == nil {
+		return ErrUnknownAccount
+	}
+	if *src < amount {
+		return ErrInsufficientFunds
+	}
+	*src -= amount
+	*dst += amount
+	w.Ledger.Record(ledger.Entry{Tick: w.Ticks, From: from, To: to, Amount: amount, Reason: reason})
+	return nil
+}
+
+// Deposit records coins that an account already holds as having arrived
+// from outside the world. Only two kinds of money arrive this way: the funds
+// a scenario starts with (ledger.ReasonInitial) and the savings immigrants
+// bring (ledger.ReasonImmigrated).
+func (w *World) Deposit(to uuid.UUID, amount int64, reason string) {
+	if amount <= 0 {
+		return
+	}
+	w.Ledger.Record(ledger.Entry{Tick: w.Ticks, From: ledger.External, To: to, Amount: amount, Reason: reason})
+}
+
+// withdraw takes coins out of the world, e.g. with an emigrant.
+func (w *World) withdraw(from uuid.UUID, amount int64, reason string) {
+	if amount <= 0 {
+		return
+	}
+	w.Ledger.Record(ledger.Entry{Tick: w.Ticks, From: from, To: ledger.External, Amount: amount, Reason: reason})
+}
+
+// Mint creates coins at a mint building and pays them into the treasury.
+// It is the only way money is created inside the world; the only other
+// money is what Deposit records coming in from outside.
+func (w *World) Mint(mintID uuid.UUID, amount int64) error {
+	i := w.buildingIndex(mintID)
+	if i < 0 {
+		return ErrBuildingNotFound
+	}
+	if w.Buildings[i].Kind != constructions.BuildingMint {
+		return ErrNotAMint
+	}
+	if amount <= 0 {
+		return nil
+	}
+	w.Castle.Treasury += amount
+	w.Castle.Current.Minted += amount
+	w.Ledger.Record(ledger.Entry{Tick: w.Ticks, From: ledger.External, To: ledger.Treasury, Amount: amount, Reason: ledger.ReasonMint})
+	return nil
+}
+
+// MoneySupply sums the coins held by agents, buildings, households and the
+// treasury.
+// It equals Ledger.Supply() as long as money is conserved.
+func (w *World) MoneySupply() int64 {
+	total := w.Castle.Treasury
+	for i := range w.Agents {
+		total += w.Agents[i].Money
+	}
+	for i := range w.Buildings {
+		total += w.Buildings[i].Money
+	}
+	for i := range w.Households {
+		total += w.Households[i].Money
+	}
+	return total
+}
+
+func (w *World) economyTick(dt time.Duration) {
+	rules := w.Economy
+	if rules.ShiftLength > 0 {
+		w.sinceShift += dt
+		if w.sinceShift >= rules.ShiftLength {
+			w.sinceShift -= rules.ShiftLength
+			w.assignJobs()
+			w.payWages()
+			w.runMints()
+		}
+	}
+	w.sinceDay += dt
+	if w.sinceDay >= w.Calendar.DayLength {
+		w.sinceDay -= w.Calendar.DayLength
+		w.poolBudgets()
+		w.collectRent()
+		w.taxMood()
+		r := w.Castle.Close(w.Ticks)
+		w.Emit(events.FiscalPeriodClosed{Period: r.Period, Income: r.Income(), Wages: r.Wages, Treasury: r.TreasuryEnd})
+	}
+}
+
+// isWorkplace reports whether a building employs workers.
+func isWorkplace(b *constructions.Building) bool {
+	return b.Kind != constructions.BuildingHouse && b.Capacity > 0
+}
+
+// assignJobs drops workers who left the world and fills open positions with
+// unemployed agents in agent order. Agents only take jobs at workplaces they
+// know of, and idle archetypes never take jobs.
+func (w *World) assignJobs() {
+	for i := range w.Buildings {
+		b := &w.Buildings[i]
+		if !isWorkplace(b) {
+			continue
+		}
+		kept := b.Workers[:0]
+		for _, id := range b.Workers {
+			if w.agentIndex(id) >= 0 {
+				kept = append(kept, id)
+			}
+		}
+		b.Workers = kept
+	}
+
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if a.Workplace != uuid.Nil || a.Behaviour == agents.BehaviourIdle {
+			continue
+		}
+		for k := range w.Buildings {
+			b := &w.Buildings[k]
+			if isWorkplace(b) && len(b.Workers) < b.Capacity && a.Memory.Knows(b.ID) {
+				b.Workers = append(b.Workers, a.ID)
+				a.Workplace = b.ID
+				break
+			}
+		}
+	}
+}
+
+// payWages pays every worker for the shift and withholds income tax.
+// Markets pay from their own takings; public buildings are paid by the
+// treasury. Unpaid workers lose happiness.
+func (w *World) payWages() {
+	wage := w.Economy.Wage
+	for i := range w.Buildings {
+		b := &w.Buildings[i]
+		payer := ledger.Treasury
+		if b.Kind == constructions.BuildingMarket {
+			payer = b.ID
+		}
+		for _, id := range b.Workers {
+			if err := w.Transfer(payer, id, wage, ledger.ReasonWage); err != nil {
+				w.adjustHappiness(id, -0.05)
+				continue
+			}
+			if payer == ledger.Treasury {
+				w.Castle.Current.Wages += wage
+			}
+			w.Castle.Current.IncomeTax += w.levy(id, wage, w.Castle.Policy.IncomeTax, ledger.ReasonIncomeTax)
+		}
+	}
+}
+
+// taxDebt is who owes a fraction of a coin of tax, and for what.
+type taxDebt struct {
+	account uuid.UUID
+	reason  string
+}
+
+// levy charges account tax at rate on amount and pays it to the treasury
+// in whole coins. Fractions of a coin are carried over to the account's
+// next charge for the same reason, so that even payments too small to be
+// taxed on their own add up. It returns the coins collected.
+func (w *World) levy(account uuid.UUID, amount int64, rate float64, reason string) int64 {
+	if w.owed == nil {
+		w.owed = map[taxDebt]float64{}
+	}
+	debt := taxDebt{account, reason}
+	owed := w.owed[debt] + float64(amount)*rate
+	// The epsilon keeps sums such as ten charges of 0.1 from falling just
+	// short of a coin.
+	tax := int64(owed + 1e-9)
+	if tax > 0 && w.Transfer(account, ledger.Treasury, tax, reason) != nil {
+		tax = 0
+	}
+	w.owed[debt] = owed - float64(tax)
+	return tax
+}
+
+// forgive drops what an account leaving the world still owes.
+func (w *World) forgive(account uuid.UUID) {
+	delete(w.owed, taxDebt{account, ledger.ReasonIncomeTax})
+	delete(w.owed, taxDebt{account, ledger.ReasonSalesTax})
+}
+
+func (w *World) runMints() {
+	for i := range w.Buildings {
+		b := &w.Buildings[i]
+		if b.Kind == constructions.BuildingMint && len(b.Workers) > 0 {
+			w.Mint(b.ID, w.Economy.MintPerWorker*int64(len(b.Workers)))
+		}
+	}
+}
+
+// collectRent charges every agent the daily rent. Agents who cannot pay
+// lose happiness.
+func (w *World) collectRent() {
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if err := w.Transfer(a.ID, ledger.Treasury, w.Economy.Rent, ledger.ReasonRent); err != nil {
+			a.Happiness = math.Max(a.Happiness-0.1, 0)
+			continue
+		}
+		w.Castle.Current.Rent += w.Economy.Rent
+	}
+}
+
+// taxMood makes agents happier under light taxes and unhappier under heavy
+// ones, which in turn drives emigration and immigration.
+func (w *World) taxMood() {
+	delta := w.Economy.TaxMood * (w.Economy.TaxComfort - w.Castle.Policy.Burden())
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		a.Happiness = math.Min(math.Max(a.Happiness+delta, 0), 1)
+	}
+}
+
+// SetPolicy changes the castle's tax policy at runtime.
+func (w *World) SetPolicy(u castle.PolicyUpdate) error {
+	if err := w.Castle.SetPolicy(u); err != nil {
+		return err
+	}
+	p := w.Castle.Policy
+	w.Emit(events.PolicyChanged{IncomeTax: p.IncomeTax, SalesTax: p.SalesTax, Tithe: p.Tithe, Toll: p.Toll})
+	return nil
+}
+
+// Produce adds goods made at a building, or harvested into a store, to its
+// stock after the castle takes its tithe in kind, and returns how many units
+// were kept.
+func (w *World) Produce(buildingID uuid.UUID, g goods.Good, qty int) int {
+	stock, _, ok := w.store(buildingID)
+	if !ok || qty <= 0 {
+		return 0
+	}
+	tithe := int(castle.Tax(int64(qty), w.Castle.Policy.Tithe))
+	w.Castle.Stores.Add(g, tithe)
+	w.Castle.Current.Tithe[string(g)] += tithe
+	kept := qty - tithe
+	stock.Add(g, kept)
+	w.Emit(events.GoodsProduced{BuildingID: buildingID, Good: string(g), Qty: qty, Tithe: tithe})
+	return kept
+}
+
+// chargeTolls charges agents that just entered the castle through the gate.
+// Those who live or work inside the walls pass free.
+func (w *World) chargeTolls(entered []uuid.UUID) {
+	toll := w.Castle.Policy.Toll
+	for _, id := range entered {
+		if w.resident(&w.Agents[w.agentIndex(id)]) {
+			continue
+		}
+		if w.Transfer(id, ledger.Treasury, toll, ledger.ReasonToll) == nil {
+			w.Castle.Current.Tolls += toll
+		}
+	}
+}
+
+// resident reports whether a's home or workplace is inside the castle.
+func (w *World) resident(a *agents.Agent) bool {
+	for _, id := range [...]uuid.UUID{a.Home, a.Workplace} {
+		if i := w.buildingIndex(id); i >= 0 {
+			b := &w.Buildings[i]
+			if w.insideCastle((b.MinX+b.MaxX)/2, (b.MinZ+b.MaxZ)/2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// insideCastle reports whether a point lies within the castle walls.
+func (w *World) insideCastle(x, z float64) bool {
+	if w.Map == nil {
+		return false
+	}
+	c := w.Map.Castle
+	return x >= c.MinX && x < c.MaxX && z >= c.MinZ && z < c.MaxZ
+}
+
+func (w *World) adjustHappiness(id uuid.UUID, delta float64) {
+	if i := w.agentIndex(id); i >= 0 {
+		a := &w.Agents[i]
+		a.Happiness = math.Min(math.Max(a.Happiness+delta, 0), 1)
+	}
+}
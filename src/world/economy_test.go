@@ -0,0 +1,145 @@
+package world_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/ledger"
+	"veatla/simulator/src/scenario"
+	"veatla/simulator/src/world"
+
+	"github.com/google/uuid"
+)
+
+const step = 50 * time.Millisecond
+
+// small is a scenario that runs quickly but has agents, households, shops
+// and pay days.
+func small(seed int64, agents int) scenario.Config {
+	cfg := scenario.Default(seed)
+	cfg.Agents = agents
+	cfg.DayLength = time.Minute
+	return cfg
+}
+
+func conserved(t *testing.T, w *world.World) {
+	t.Helper()
+	if got, want := w.MoneySupply(), w.Ledger.Supply(); got != want {
+		t.Fatalf("tick %d: money supply %d, ledger says %d", w.Ticks, got, want)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	w := scenario.Build(small(1, 2))
+	a, b := w.Agents[0].ID, w.Agents[1].ID
+	start := w.Agents[1].Money
+
+	if err := w.Transfer(ledger.Treasury, a, 10, ledger.ReasonWage); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Transfer(a, b, w.Agents[0].Money, ledger.ReasonTrade); err != nil {
+		t.Fatal(err)
+	}
+	if w.Agents[0].Money != 0 || w.Agents[1].Money <= start+10 {
+		t.Fatalf("balances after transfer: %d, %d", w.Agents[0].Money, w.Agents[1].Money)
+	}
+	for _, tc := range []struct {
+		name     string
+		from, to uuid.UUID
+		amount   int64
+		want     error
+	}{
+		{"overdraw", a, b, 1, world.ErrInsufficientFunds},
+		{"unknown payer", uuid.New(), b, 1, world.ErrUnknownAccount},
+		{"unknown payee", b, uuid.New(), 1, world.ErrUnknownAccount},
+		{"nothing", a, b, 0, nil},
+	} {
+		if err := w.Transfer(tc.from, tc.to, tc.amount, ledger.ReasonTrade); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	conserved(t, w)
+}
+
+func TestTransferAfterRemoval(t *testing.T) {
+	w := scenario.Build(small(2, 6))
+	w.RemoveAgent(w.Agents[0].ID, events.ReasonDied)
+	w.RemoveAgent(w.Agents[1].ID, events.ReasonEmigrated)
+	conserved(t, w)
+
+	// Accounts after the removed ones moved down and must still resolve.
+	last := &w.Agents[len(w.Agents)-1]
+	before := last.Money
+	if err := w.Transfer(ledger.Treasury, last.ID, 5, ledger.ReasonWage); err != nil {
+		t.Fatal(err)
+	}
+	if last.Money != before+5 {
+		t.Fatalf("paid the wrong account: %d, want %d", last.Money, before+5)
+	}
+	conserved(t, w)
+}
+
+func TestMoneyConserved(t *testing.T) {
+	w := scenario.Build(small(3, 30))
+	conserved(t, w)
+	for range 600 {
+		w.Tick(step)
+		conserved(t, w)
+	}
+}
+
+func TestDefaultPolicyTaxesWages(t *testing.T) {
+	w := scenario.Build(small(4, 30))
+	// A shift every tick, so wages are paid far more often than the books
+	// close.
+	w.Economy.ShiftLength = step
+	// Each 4-coin wage owes 0.4 coins at 10%, which rounds to nothing on its
+	// own.
+	if w.Economy.Wage != 4 || w.Castle.Policy.IncomeTax != 0.1 {
+		t.Fatalf("default wage %d at %v", w.Economy.Wage, w.Castle.Policy.IncomeTax)
+	}
+	var paid int64
+	for range 40 {
+		w.Tick(step)
+	}
+	for _, e := range w.Ledger.Entries(ledger.Treasury) {
+		if e.Reason == ledger.ReasonIncomeTax && e.To == ledger.Treasury {
+			paid += e.Amount
+		}
+	}
+	if w.Castle.Current.Wages == 0 {
+		t.Fatal("no wages were paid")
+	}
+	if paid == 0 || w.Castle.Current.IncomeTax != paid {
+		t.Fatalf("income tax: ledger %d, report %d; want equal and above zero", paid, w.Castle.Current.IncomeTax)
+	}
+	conserved(t, w)
+}
+
+func TestMoneyOnlyEntersByKnownRoutes(t *testing.T) {
+	w := scenario.Build(small(9, 10))
+	w.Population.ImmigrationRate = 1000
+	w.Population.ProsperousAbove = 0
+	for range 400 {
+		w.Tick(step)
+	}
+	var immigrated bool
+	for _, e := range w.Ledger.Entries(ledger.External) {
+		if e.From != ledger.External {
+			continue
+		}
+		switch e.Reason {
+		case ledger.ReasonImmigrated:
+			immigrated = true
+		case ledger.ReasonInitial, ledger.ReasonMint:
+		default:
+			t.Errorf("tick %d: %d coins came in from outside as %q", e.Tick, e.Amount, e.Reason)
+		}
+	}
+	if !immigrated {
+		t.Error("no immigrant brought savings")
+	}
+	conserved(t, w)
+}
@@ -0,0 +1,303 @@
+package world
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/farming"
+	"veatla/simulator/src/generator"
+	"veatla/simulator/src/goods"
+	spatialhash "veatla/simulator/src/spatial-hash"
+	"veatla/simulator/src/zones"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnknownCrop = errors.New("unknown crop")
+
+// fieldWork is the farmer-days of labour per tile to till, sow or harvest.
+const fieldWork = 0.01
+
+// defaultSoil is the fertility assumed when there is no map.
+const defaultSoil = 0.6
+
+// fieldJob ties a farmer to a field: working it (stepWork), collecting its
+// harvest (stepFetch) or carrying the harvest to a store (stepDeliver).
+type fieldJob struct {
+	field uuid.UUID
+	step  jobStep
+	store uuid.UUID
+	good  goods.Good
+	until int
+}
+
+// farmingTick grows every field and advances every farmer's job.
+func (w *World) farmingTick(dt time.Duration) {
+	days := float64(dt) / float64(w.Calendar.DayLength)
+	season := w.Now().Season
+	farms := false
+	for i := range w.Zones {
+		z := &w.Zones[i]
+		if z.Kind != zones.KindFarm {
+			continue
+		}
+		farms = true
+		if !farming.Grow(z, farming.Crops[z.Crop], season, w.Weather.Growth(), days) {
+			continue
+		}
+		if z.Stage == zones.StageRotten {
+			w.Emit(events.CropRotted{ZoneID: z.ID, Crop: z.Crop})
+		} else {
+			w.Emit(events.FieldWorked{ZoneID: z.ID, Stage: string(z.Stage)})
+		}
+	}
+	if !farms && len(w.fieldJobs) == 0 {
+		return
+	}
+	if w.fieldJobs == nil {
+		w.fieldJobs = map[uuid.UUID]*fieldJob{}
+	}
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if a.Behaviour != agents.BehaviourFarm {
+			continue
+		}
+		duty := w.onDuty(a)
+		j, ok := w.fieldJobs[a.ID]
+		if ok && j.step == stepWait && w.Ticks >= j.until {
+			delete(w.fieldJobs, a.ID)
+			ok = false
+		}
+		if ok && j.step == stepWork && !duty {
+			w.endFieldJob(a)
+			ok = false
+		}
+		if !ok {
+			j = w.findFieldJob(a, season, duty)
+			if j == nil {
+				continue
+			}
+			w.fieldJobs[a.ID] = j
+			w.startFieldStep(a, j)
+			continue
+		}
+		w.advanceFieldJob(a, j, days)
+	}
+}
+
+// findFieldJob has a farmer carry its harvest home first, then, while on
+// duty, work the first field it knows of that needs it or collect a harvest
+// left in the field.
+func (w *World) findFieldJob(a *agents.Agent, season calendar.Season, duty bool) *fieldJob {
+	for _, g := range a.Inventory.Items.Goods() {
+		if !farming.Harvests(g) {
+			continue
+		}
+		if store, ok := w.nearestStore(a); ok {
+			return &fieldJob{step: stepDeliver, store: store, good: g}
+		}
+	}
+	if !duty {
+		return nil
+	}
+	for i := range w.Zones {
+		z := &w.Zones[i]
+		if z.Kind != zones.KindFarm || !a.Memory.Knows(z.ID) {
+			continue
+		}
+		if fieldNeedsWork(z, season) {
+			return &fieldJob{field: z.ID, step: stepWork}
+		}
+		if len(z.Stock) > 0 && a.Inventory.Room(z.Stock.Goods()[0]) > 0 {
+			return &fieldJob{field: z.ID, step: stepFetch}
+		}
+	}
+	return nil
+}
+
+// fieldNeedsWork reports whether a field can be tilled, sown or harvested
+// now. Fallow fields are only tilled when their crop can be sown, so they
+// rest out of season.
+func fieldNeedsWork(z *zones.Zone, season calendar.Season) bool {
+	crop := farming.Crops[z.Crop]
+	switch z.Stage {
+	case zones.StageRotten, zones.StageRipe:
+		return true
+	case zones.StageFallow, zones.StageTilled:
+		return crop.CanSow(season)
+	}
+	return false
+}
+
+// startFieldStep sends the farmer to where its current step happens.
+func (w *World) startFieldStep(a *agents.Agent, j *fieldJob) {
+	var x, z float64
+	ok := false
+	if j.step == stepDeliver {
+		x, z, ok = w.storeApproach(j.store)
+	} else if zi := w.zoneIndex(j.field); zi >= 0 {
+		x, z = w.Zones[zi].Anchor()
+		ok = true
+	}
+	if !ok {
+		delete(w.fieldJobs, a.ID)
+		return
+	}
+	a.SetGoal(w, x, z)
+	if a.GoalFailed() {
+		w.fieldJobs[a.ID] = &fieldJob{step: stepWait, until: w.Ticks + jobRetry}
+	}
+}
+
+func (w *World) advanceFieldJob(a *agents.Agent, j *fieldJob, days float64) {
+	if j.step == stepWait {
+		return
+	}
+	if j.step == stepDeliver {
+		// A store can be unloaded from anywhere within reach, which spares
+		// the farmer the last steps around a crowded door.
+		if _, box, ok := w.store(j.store); ok && box.DistanceTo(a.X, a.Z) <= reach {
+			n := a.Inventory.Take(j.good, a.Inventory.Count(j.good))
+			w.Produce(j.store, j.good, n)
+			w.endFieldJob(a)
+			return
+		}
+	}
+	if a.GoalFailed() {
+		w.fieldJobs[a.ID] = &fieldJob{step: stepWait, until: w.Ticks + jobRetry}
+		return
+	}
+	if !a.AtGoal() || j.step == stepDeliver {
+		return
+	}
+	zi := w.zoneIndex(j.field)
+	if zi < 0 {
+		w.endFieldJob(a)
+		return
+	}
+	z := &w.Zones[zi]
+
+	if j.step == stepFetch {
+		for _, g := range z.Stock.Goods() {
+			n := z.Stock.Take(g, a.Inventory.Room(g))
+			a.Inventory.Add(g, n)
+			if n > 0 {
+				w.Emit(events.GoodsPickedUp{AgentID: a.ID, SourceID: z.ID, Good: string(g), Qty: n})
+			}
+		}
+		w.endFieldJob(a)
+		return
+	}
+
+	if !fieldNeedsWork(z, w.Now().Season) {
+		w.endFieldJob(a)
+		return
+	}
+	z.Work += a.Skills["farming"] * w.Weather.Work() * days / (z.Area() * fieldWork)
+	if z.Work < 1 {
+		return
+	}
+	crop := farming.Crops[z.Crop]
+	qty := 0
+	switch z.Stage {
+	case zones.StageFallow, zones.StageRotten:
+		z.Till()
+	case zones.StageTilled:
+		z.Sow()
+	case zones.StageRipe:
+		qty = farming.Yield(z, crop)
+		z.Harvest()
+		farming.Exhaust(z)
+		z.Stock.Add(crop.Yield, qty)
+	}
+	w.Emit(events.FieldWorked{ZoneID: z.ID, Stage: string(z.Stage), Qty: qty})
+	w.endFieldJob(a)
+}
+
+func (w *World) endFieldJob(a *agents.Agent) {
+	delete(w.fieldJobs, a.ID)
+	a.ClearGoal()
+}
+
+// releaseFarmers ends every job at a field.
+func (w *World) releaseFarmers(field uuid.UUID) {
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if j, ok := w.fieldJobs[a.ID]; ok && j.field == field && j.step != stepDeliver {
+			w.endFieldJob(a)
+		}
+	}
+}
+
+// nearestStore returns the stockpile or storage zone the agent knows of
+// closest to it.
+func (w *World) nearestStore(a *agents.Agent) (uuid.UUID, bool) {
+	best, bestDist := uuid.Nil, math.Inf(1)
+	for i := range w.Buildings {
+		b := &w.Buildings[i]
+		if b.Kind != constructions.BuildingStockpile || !a.Memory.Knows(b.ID) {
+			continue
+		}
+		box := spatialhash.AABB{MinX: b.MinX, MinZ: b.MinZ, MaxX: b.MaxX, MaxZ: b.MaxZ}
+		if d := box.DistanceTo(a.X, a.Z); d < bestDist {
+			best, bestDist = b.ID, d
+		}
+	}
+	for i := range w.Zones {
+		zi := &w.Zones[i]
+		if zi.Kind != zones.KindStorage || !a.Memory.Knows(zi.ID) {
+			continue
+		}
+		if d := zoneBox(zi).DistanceTo(a.X, a.Z); d < bestDist {
+			best, bestDist = zi.ID, d
+		}
+	}
+	return best, best != uuid.Nil
+}
+
+// soilFertility averages the fertility of the tiles a field covers: grass
+// is fair, forest and hills poor, and farmland deposits best.
+func (w *World) soilFertility(z *zones.Zone) float64 {
+	if w.Map == nil {
+		return defaultSoil
+	}
+	m := w.Map
+	var sum float64
+	n := 0
+	for tz := math.Floor(z.MinZ/m.CellSize) * m.CellSize; tz < z.MaxZ; tz += m.CellSize {
+		for tx := math.Floor(z.MinX/m.CellSize) * m.CellSize; tx < z.MaxX; tx += m.CellSize {
+			cx, cz := tx+m.CellSize/2, tz+m.CellSize/2
+			if !z.Contains(cx, cz) {
+				continue
+			}
+			n++
+			sum += tileFertility(m, cx, cz)
+		}
+	}
+	if n == 0 {
+		return defaultSoil
+	}
+	return sum / float64(n)
+}
+
+func tileFertility(m *generator.Map, x, z float64) float64 {
+	for _, d := range m.Deposits {
+		if d.Kind == generator.DepositFarmland && math.Hypot(d.X-x, d.Z-z) <= d.Radius {
+			return 1
+		}
+	}
+	switch m.TileAt(x, z) {
+	case generator.TerrainGrass:
+		return defaultSoil
+	case generator.TerrainForest:
+		return 0.4
+	case generator.TerrainHill:
+		return 0.3
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package world
+
+import (
+	"errors"
+
+	"veatla/simulator/src/castle"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/generator"
+	spatialhash "veatla/simulator/src/spatial-hash"
+	worldQuery "veatla/simulator/src/world-query"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFortificationNotFound = errors.New("fortification not found")
+	ErrNotAGate              = errors.New("fortification is not a gate")
+)
+
+// AddFortification places a wall segment, tower or gate. Walls and towers
+// are obstacles in the grid; gates are checked by IsPointBlocked so they can
+// open and close.
+func (w *World) AddFortification(f constructions.Fortification) {
+	w.Fortifications = append(w.Fortifications, f)
+	if f.Kind != constructions.FortGate {
+		w.AddObstacle(f.Obstacle)
+	}
+}
+
+// fortify registers the generated castle's walls and towers, which the map
+// has already placed as obstacles, and adds its gate.
+func (w *World) fortify(c generator.Castle) {
+	for _, o := range c.Walls {
+		w.Fortifications = append(w.Fortifications, constructions.Fortification{Obstacle: o, Kind: constructions.FortWall})
+	}
+	for _, o := range c.Towers {
+		w.Fortifications = append(w.Fortifications, constructions.Fortification{Obstacle: o, Kind: constructions.FortTower})
+	}
+	if c.Gate == (generator.Gate{}) {
+		return
+	}
+	g := constructions.CreateFortification(w.NewID(), constructions.FortGate, c.Gate.MinX, c.Gate.MinZ, c.Gate.MaxX, c.Gate.MaxZ)
+	g.Factions = []string{castle.Faction}
+	w.AddFortification(g)
+}
+
+// Gates returns the gates, open or closed.
+func (w *World) Gates() []constructions.Fortification {
+	var gates []constructions.Fortification
+	for _, f := range w.Fortifications {
+		if f.Kind == constructions.FortGate {
+			gates = append(gates, f)
+		}
+	}
+	return gates
+}
+
+// SetGate opens or closes a gate. Closing it sends agents it now stops back
+// out of the gateway and replans every path that went through it.
+func (w *World) SetGate(id uuid.UUID, open bool) error {
+	i := w.fortificationIndex(id)
+	if i < 0 {
+		return ErrFortificationNotFound
+	}
+	g := &w.Fortifications[i]
+	if g.Kind != constructions.FortGate {
+		return ErrNotAGate
+	}
+	if g.Open == open {
+		return nil
+	}
+	g.Open = open
+	w.Emit(events.GateToggled{GateID: id, Open: open})
+	if open {
+		return nil
+	}
+	for j := range w.Agents {
+		a := &w.Agents[j]
+		if !g.Blocks(a.Faction) {
+			continue
+		}
+		if g.Contains(a.X, a.Z) {
+			w.eject(a, spatialhash.AABB{MinX: g.MinX, MinZ: g.MinZ, MaxX: g.MaxX, MaxZ: g.MaxZ})
+		}
+		if pathEnters(a.X, a.Z, a.PathAhead(), g.Contains) {
+			a.Replan(w)
+		}
+	}
+	return nil
+}
+
+// gateBlocks reports whether a closed gate stops who at (x, z).
+func (w *World) gateBlocks(x, z float64, who worldQuery.Passer) bool {
+	for i := range w.Fortifications {
+		f := &w.Fortifications[i]
+		if f.Contains(x, z) && f.Kind == constructions.FortGate && f.Blocks(who.Faction) {
+			return true
+		}
+	}
+	return false
+}
+
+// onFortification reports whether box overlaps a gate; walls and towers are
+// already obstacles in the grid.
+func (w *World) onFortification(box spatialhash.AABB) bool {
+	for _, f := range w.Fortifications {
+		if f.Kind == constructions.FortGate && box.Overlaps(spatialhash.AABB{MinX: f.MinX, MinZ: f.MinZ, MaxX: f.MaxX, MaxZ: f.MaxZ}) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *World) fortificationIndex(id uuid.UUID) int {
+	for i := range w.Fortifications {
+		if w.Fortifications[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,105 @@
+package world
+
+import (
+	"errors"
+	"testing"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/castle"
+	"veatla/simulator/src/constructions"
+	worldQuery "veatla/simulator/src/world-query"
+)
+
+func castleWorld(t *testing.T) (*World, constructions.Fortification) {
+	t.Helper()
+	w := NewWorld(1, 50, 50)
+	w.GenerateMap()
+	gates := w.Gates()
+	if len(gates) != 1 {
+		t.Fatalf("%d gates, want 1", len(gates))
+	}
+	return &w, gates[0]
+}
+
+func TestGateBlocksStrangersWhenClosed(t *testing.T) {
+	w, g := castleWorld(t)
+	x, z := (g.MinX+g.MaxX)/2, (g.MinZ+g.MaxZ)/2
+	stranger := worldQuery.Passer{}
+	guard := worldQuery.Passer{Faction: castle.Faction}
+
+	for _, tc := range []struct {
+		open            bool
+		stranger, guard bool
+	}{
+		{open: true},
+		{open: false, stranger: true},
+		{open: true},
+	} {
+		if err := w.SetGate(g.ID, tc.open); err != nil {
+			t.Fatal(err)
+		}
+		if got := w.IsPointBlocked(x, z, stranger); got != tc.stranger {
+			t.Errorf("open %v: stranger blocked = %v, want %v", tc.open, got, tc.stranger)
+		}
+		if got := w.IsPointBlocked(x, z, guard); got != tc.guard {
+			t.Errorf("open %v: castle faction blocked = %v, want %v", tc.open, got, tc.guard)
+		}
+	}
+	if err := w.SetGate(w.Fortifications[0].ID, false); !errors.Is(err, ErrNotAGate) {
+		t.Errorf("closing a wall: err = %v, want %v", err, ErrNotAGate)
+	}
+}
+
+func TestTollsSpareResidentsAndWorkers(t *testing.T) {
+	w, g := castleWorld(t)
+	c := w.Map.Castle
+	add := func(kind constructions.BuildingKind, x, z float64) constructions.Building {
+		b := constructions.CreateBuilding(w.NewID(), kind, x, z, x+2, z+2, 2)
+		w.AddBuilding(b)
+		return b
+	}
+	house := add(constructions.BuildingHouse, c.MinX+2, c.MinZ+2)
+	shop := add(constructions.BuildingStockpile, c.MinX+6, c.MinZ+2)
+	hut := add(constructions.BuildingHouse, 1, 1)
+	if w.insideCastle(1, 1) {
+		t.Fatal("map corner is inside the castle")
+	}
+
+	// Everyone walks in through the gate in the same tick.
+	x := (g.MinX + g.MaxX) / 2
+	outside, inside := g.MaxZ+0.5, g.MinZ-0.5
+	if w.insideCastle(x, outside) || !w.insideCastle(x, inside) {
+		t.Fatal("gate does not lead into the castle")
+	}
+	def := mustArchetype(t, w.Archetypes)
+	homes := []struct{ home, work constructions.Building }{
+		{home: house},
+		{home: hut, work: shop},
+		{home: hut},
+	}
+	for _, h := range homes {
+		a := agents.CreateFromArchetypeAt(w, def, x, outside)
+		a.Home, a.Workplace = h.home.ID, h.work.ID
+		a.Money = 10
+		w.AddAgent(a)
+	}
+	b := w.tickBuffers(len(w.Agents))
+	for i := range w.Agents {
+		b.proposals[i] = w.Agents[i]
+		b.proposals[i].Z = inside
+	}
+	w.commit(b)
+
+	toll := w.Castle.Policy.Toll
+	for i, want := range []int64{10, 10, 10 - toll} {
+		if got := w.Agents[i].Money; got != want {
+			t.Errorf("agent %d has %d coins, want %d", i, got, want)
+		}
+	}
+	if w.Castle.Current.Tolls != toll {
+		t.Errorf("tolls = %d, want %d", w.Castle.Current.Tolls, toll)
+	}
+	if got, want := w.MoneySupply(), w.Ledger.Supply(); got != want {
+		t.Errorf("money supply %d, ledger says %d", got, want)
+	}
+}
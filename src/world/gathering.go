@@ -0,0 +1,225 @@
+package world
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/goods"
+	"veatla/simulator/src/resources"
+	spatialhash "veatla/simulator/src/spatial-hash"
+
+	"github.com/google/uuid"
+)
+
+// gatherJob ties a gatherer to a node: working it (stepWork) or carrying
+// what it cut or dug to a store (stepDeliver).
+type gatherJob struct {
+	node  uuid.UUID
+	step  jobStep
+	store uuid.UUID
+	good  goods.Good
+	got   int
+	until int
+}
+
+// AddNode places a resource node and inserts its footprint into the grid.
+func (w *World) AddNode(n resources.Node) {
+	w.Nodes = append(w.Nodes, n)
+	if !n.Depleted() {
+		w.Grid.Insert(n.ID, n.MinX, n.MinZ, n.MaxX, n.MaxZ, true)
+	}
+}
+
+// gatheringTick regrows depleted nodes and advances every gatherer's job.
+func (w *World) gatheringTick(dt time.Duration) {
+	days := float64(dt) / float64(w.Calendar.DayLength)
+	for i := range w.Nodes {
+		if n := &w.Nodes[i]; n.Grow(days) {
+			w.restoreNode(n)
+		}
+	}
+	if len(w.Nodes) == 0 && len(w.gatherJobs) == 0 {
+		return
+	}
+	if w.gatherJobs == nil {
+		w.gatherJobs = map[uuid.UUID]*gatherJob{}
+	}
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if a.Behaviour != agents.BehaviourGather {
+			continue
+		}
+		duty := w.onDuty(a)
+		j, ok := w.gatherJobs[a.ID]
+		if ok && j.step == stepWait && w.Ticks >= j.until {
+			delete(w.gatherJobs, a.ID)
+			ok = false
+		}
+		if ok && j.step == stepWork && !duty {
+			w.endGatherJob(a)
+			ok = false
+		}
+		if !ok {
+			j = w.findGatherJob(a, duty)
+			if j == nil {
+				continue
+			}
+			w.gatherJobs[a.ID] = j
+			w.startGatherStep(a, j)
+			continue
+		}
+		w.advanceGatherJob(a, j, days)
+	}
+}
+
+// findGatherJob has a gatherer carry its load home first, then, while on
+// duty, work the nearest node it knows of and has the skill for.
+func (w *World) findGatherJob(a *agents.Agent, duty bool) *gatherJob {
+	for _, g := range a.Inventory.Items.Goods() {
+		if !resources.Gathers(g) {
+			continue
+		}
+		if store, ok := w.nearestStore(a); ok {
+			return &gatherJob{step: stepDeliver, store: store, good: g}
+		}
+	}
+	if !duty {
+		return nil
+	}
+	best, bestDist := -1, math.Inf(1)
+	for i := range w.Nodes {
+		n := &w.Nodes[i]
+		y := n.Yield()
+		if n.Depleted() || a.Skills[y.Skill] <= 0 || a.Inventory.Room(y.Good) == 0 || !a.Memory.Knows(n.ID) {
+			continue
+		}
+		if d := nodeBox(n).DistanceTo(a.X, a.Z); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	return &gatherJob{node: w.Nodes[best].ID, step: stepWork}
+}
+
+// startGatherStep sends the gatherer beside its node or to its store.
+func (w *World) startGatherStep(a *agents.Agent, j *gatherJob) {
+	var x, z float64
+	ok := false
+	if j.step == stepDeliver {
+		x, z, ok = w.storeApproach(j.store)
+	} else if ni := w.nodeIndex(j.node); ni >= 0 {
+		x, z, ok = w.doorPoint(w.Nodes[ni].Obstacle)
+	}
+	if !ok {
+		delete(w.gatherJobs, a.ID)
+		return
+	}
+	a.SetGoal(w, x, z)
+	if a.GoalFailed() {
+		w.gatherJobs[a.ID] = &gatherJob{step: stepWait, until: w.Ticks + jobRetry}
+	}
+}
+
+func (w *World) advanceGatherJob(a *agents.Agent, j *gatherJob, days float64) {
+	if j.step == stepWait {
+		return
+	}
+	if j.step == stepDeliver {
+		if _, box, ok := w.store(j.store); ok && box.DistanceTo(a.X, a.Z) <= reach {
+			n := a.Inventory.Take(j.good, a.Inventory.Count(j.good))
+			w.Produce(j.store, j.good, n)
+			w.endGatherJob(a)
+			return
+		}
+	}
+	ni := w.nodeIndex(j.node)
+	if j.step == stepWork && (ni < 0 || w.Nodes[ni].Depleted()) {
+		w.endGatherJob(a)
+		return
+	}
+	if a.GoalFailed() {
+		w.gatherJobs[a.ID] = &gatherJob{step: stepWait, until: w.Ticks + jobRetry}
+		return
+	}
+	if j.step == stepDeliver {
+		return
+	}
+	n := &w.Nodes[ni]
+	if !a.AtGoal() && nodeBox(n).DistanceTo(a.X, a.Z) > reach {
+		return
+	}
+
+	y := n.Yield()
+	n.Work += a.Skills[y.Skill] * w.Weather.Work() * days / y.WorkDays
+	for n.Work >= 1 && !n.Depleted() && a.Inventory.Room(y.Good) > 0 {
+		n.Work--
+		j.got += a.Inventory.Add(y.Good, n.Take(1))
+	}
+	if !n.Depleted() && a.Inventory.Room(y.Good) > 0 {
+		return
+	}
+	w.Emit(events.ResourceGathered{AgentID: a.ID, NodeID: n.ID, Good: string(y.Good), Qty: j.got})
+	w.endGatherJob(a)
+	if n.Depleted() {
+		w.depleteNode(ni)
+	}
+}
+
+func (w *World) endGatherJob(a *agents.Agent) {
+	delete(w.gatherJobs, a.ID)
+	a.ClearGoal()
+}
+
+// depleteNode clears a worked-out node's footprint. Nodes that never grow
+// back are removed altogether.
+func (w *World) depleteNode(i int) {
+	n := &w.Nodes[i]
+	id := n.ID
+	w.Grid.Remove(id)
+	w.Emit(events.NodeDepleted{NodeID: id, NodeKind: string(n.Kind)})
+	if n.Yield().RegrowDays <= 0 {
+		w.Nodes = slices.Delete(w.Nodes, i, i+1)
+	}
+	for k := range w.Agents {
+		a := &w.Agents[k]
+		if j, ok := w.gatherJobs[a.ID]; ok && j.node == id && j.step == stepWork {
+			w.endGatherJob(a)
+		}
+	}
+}
+
+// restoreNode puts a regrown node back on the map, moving aside anyone
+// standing where it grows and replanning paths through it.
+func (w *World) restoreNode(n *resources.Node) {
+	w.Grid.Insert(n.ID, n.MinX, n.MinZ, n.MaxX, n.MaxZ, true)
+	w.Emit(events.NodeRegrown{NodeID: n.ID, NodeKind: string(n.Kind)})
+	box := nodeBox(n)
+	inside := func(x, z float64) bool { return n.Contains(x, z) }
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if agentBox(a).Overlaps(box) {
+			w.eject(a, box)
+		}
+		if pathEnters(a.X, a.Z, a.PathAhead(), inside) {
+			a.Replan(w)
+		}
+	}
+}
+
+func nodeBox(n *resources.Node) spatialhash.AABB {
+	return spatialhash.AABB{MinX: n.MinX, MinZ: n.MinZ, MaxX: n.MaxX, MaxZ: n.MaxZ}
+}
+
+func (w *World) nodeIndex(id uuid.UUID) int {
+	for i := range w.Nodes {
+		if w.Nodes[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,71 @@
+package world
+
+import (
+	"testing"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/goods"
+	"veatla/simulator/src/resources"
+
+	"github.com/google/uuid"
+)
+
+// gatherWorld has a single node of the given kind and a gatherer standing
+// at its south side.
+func gatherWorld(t *testing.T, kind resources.Kind) (*World, *agents.Agent, uuid.UUID) {
+	t.Helper()
+	w := NewWorld(1, 40, 40)
+	o := constructions.CreateObstacle(w.NewID(), 10, 10, 11, 11)
+	n, err := resources.New(kind, o, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddNode(n)
+	w.AddAgent(agents.CreateFromArchetypeAt(&w, mustArchetype(t, w.Archetypes), 10.5, 12))
+	a := &w.Agents[0]
+	a.Skills = map[string]float64{n.Yield().Skill: 1}
+	w.gatherJobs = map[uuid.UUID]*gatherJob{}
+	return &w, a, o.ID
+}
+
+func TestGatheringDepletesNode(t *testing.T) {
+	w, a, id := gatherWorld(t, resources.KindTree)
+	j := &gatherJob{node: id, step: stepWork}
+	w.gatherJobs[a.ID] = j
+	arrive(w, a, a.X, a.Z)
+	w.advanceGatherJob(a, j, 1)
+
+	if n := a.Inventory.Count(goods.Wood); n != 2 {
+		t.Fatalf("gathered %d wood, want 2", n)
+	}
+	if _, ok := w.gatherJobs[a.ID]; ok {
+		t.Error("job outlived its node")
+	}
+	if _, ok := w.Grid.Bounds(id); ok {
+		t.Fatal("depleted tree still blocks the grid")
+	}
+
+	// Trees grow back and stand in the way again.
+	days := resources.Kinds[resources.KindTree].RegrowDays
+	w.gatheringTick(time.Duration(days * float64(w.Calendar.DayLength)))
+	if _, ok := w.Grid.Bounds(id); !ok || w.Nodes[0].Amount != 2 {
+		t.Fatalf("tree did not grow back: %+v", w.Nodes[0])
+	}
+}
+
+func TestDepletedRockIsRemoved(t *testing.T) {
+	w, a, id := gatherWorld(t, resources.KindRock)
+	j := &gatherJob{node: id, step: stepWork}
+	w.gatherJobs[a.ID] = j
+	arrive(w, a, a.X, a.Z)
+	w.advanceGatherJob(a, j, 1)
+
+	if n := a.Inventory.Count(goods.Stone); n != 2 {
+		t.Fatalf("gathered %d stone, want 2", n)
+	}
+	if len(w.Nodes) != 0 {
+		t.Fatalf("%d nodes left, want the rock gone", len(w.Nodes))
+	}
+}
@@ -0,0 +1,231 @@
+package world
+
+import (
+	"math"
+	"slices"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/goods"
+	"veatla/simulator/src/households"
+	"veatla/simulator/src/ledger"
+	spatialhash "veatla/simulator/src/spatial-hash"
+
+	"github.com/google/uuid"
+)
+
+// pantryPerMember is the food a household tries to keep at home for each
+// member.
+const pantryPerMember = 2
+
+// householdTick lets members at home store spare food in the pantry and eat
+// from it when hungry.
+func (w *World) householdTick() {
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if a.Household == uuid.Nil || !a.AtHome() || w.busy(a.ID) {
+			continue
+		}
+		hi := w.householdIndex(a.Household)
+		if hi < 0 {
+			continue
+		}
+		pantry := w.Households[hi].Pantry
+		for _, g := range a.Inventory.Items.Goods() {
+			if agents.IsFood(g) {
+				pantry.Add(g, a.Inventory.Take(g, a.Inventory.Count(g)))
+			}
+		}
+		if !a.Hungry() {
+			continue
+		}
+		for _, g := range []goods.Good{goods.Bread, goods.Grain} {
+			// Check for room first: whatever Add refuses would be lost.
+			if a.Inventory.Room(g) > 0 && a.Inventory.Add(g, pantry.Take(g, 1)) == 1 {
+				a.Eat()
+				break
+			}
+		}
+	}
+}
+
+// settleHomeless moves homeless agents into the nearest house they know of
+// with room, forming a new household in an empty one.
+func (w *World) settleHomeless() {
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if a.Household != uuid.Nil {
+			continue
+		}
+		if hi := w.vacancy(a); hi >= 0 {
+			w.joinHousehold(a, hi)
+		}
+	}
+}
+
+// vacancy returns the household of the nearest house the agent knows of
+// with room, or -1 if there is none. Agents move in with an existing
+// household before they form a new one in an empty house.
+func (w *World) vacancy(a *agents.Agent) int {
+	best, bestDist, bestEmpty := -1, math.Inf(1), true
+	for i := range w.Buildings {
+		b := &w.Buildings[i]
+		n := w.residents(b.ID)
+		if b.Kind != constructions.BuildingHouse || n >= b.Capacity || !a.Memory.Knows(b.ID) {
+			continue
+		}
+		box := spatialhash.AABB{MinX: b.MinX, MinZ: b.MinZ, MaxX: b.MaxX, MaxZ: b.MaxZ}
+		d := box.DistanceTo(a.X, a.Z)
+		if empty := n == 0; (bestEmpty && !empty) || (empty == bestEmpty && d < bestDist) {
+			best, bestDist, bestEmpty = i, d, empty
+		}
+	}
+	if best < 0 {
+		return -1
+	}
+	home := w.Buildings[best].ID
+	if hi := w.householdAt(home); hi >= 0 {
+		return hi
+	}
+	h := households.New(w.NewID(), home)
+	w.Households = append(w.Households, h)
+	reindex(&w.householdSlot, w.Households, len(w.Households)-1, householdID)
+	w.Emit(events.HouseholdFormed{HouseholdID: h.ID, HomeID: home})
+	return len(w.Households) - 1
+}
+
+func (w *World) joinHousehold(a *agents.Agent, hi int) {
+	h := &w.Households[hi]
+	h.Join(a.ID)
+	a.Household, a.Home = h.ID, h.Home
+	w.Emit(events.HouseholdJoined{HouseholdID: h.ID, AgentID: a.ID, HomeID: h.Home})
+}
+
+// leaveHousehold takes a departing agent out of its household. The dead
+// leave their money and food to the household and their other goods to its
+// eldest member. When the last member goes the household is dissolved: an
+// emigrant takes the budget along, while on a death it and the pantry fall
+// to the castle.
+func (w *World) leaveHousehold(a *agents.Agent, reason string) {
+	hi := w.householdIndex(a.Household)
+	a.Household, a.Home = uuid.Nil, uuid.Nil
+	if hi < 0 {
+		return
+	}
+	h := &w.Households[hi]
+	if h.Leave(a.ID) {
+		if reason == events.ReasonDied {
+			w.Transfer(a.ID, h.ID, a.Money, ledger.ReasonInheritance)
+			w.bequeath(a, h)
+		}
+		return
+	}
+	if reason == events.ReasonDied {
+		w.Transfer(h.ID, ledger.Treasury, h.Money, ledger.ReasonInheritance)
+		for g, n := range h.Pantry {
+			w.Castle.Stores.Add(g, n)
+		}
+	} else {
+		w.Transfer(h.ID, a.ID, h.Money, ledger.ReasonHousehold)
+	}
+	w.Emit(events.HouseholdDissolved{HouseholdID: h.ID, HomeID: h.Home})
+	delete(w.householdSlot, h.ID)
+	w.Households = slices.Delete(w.Households, hi, hi+1)
+	reindex(&w.householdSlot, w.Households, hi, householdID)
+}
+
+// bequeath hands a dead agent's goods to its household. Whatever the heir
+// cannot carry is dropped where the agent died.
+func (w *World) bequeath(a *agents.Agent, h *households.Household) {
+	var heir *agents.Agent
+	for _, id := range h.Members {
+		if i := w.agentIndex(id); i >= 0 && (heir == nil || w.Agents[i].Age > heir.Age) {
+			heir = &w.Agents[i]
+		}
+	}
+	for _, g := range a.Inventory.Items.Goods() {
+		n := a.Inventory.Count(g)
+		if agents.IsFood(g) {
+			h.Pantry.Add(g, a.Inventory.Take(g, n))
+		} else if heir != nil {
+			a.Inventory.Take(g, heir.Inventory.Add(g, n))
+		}
+	}
+}
+
+// poolBudgets has every household member pay what it holds above the
+// allowance into the shared budget, then tops up members short of it from
+// what the budget has.
+func (w *World) poolBudgets() {
+	allowance := w.Economy.Allowance
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if a.Household != uuid.Nil && a.Money > allowance {
+			w.Transfer(a.ID, a.Household, a.Money-allowance, ledger.ReasonHousehold)
+		}
+	}
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if a.Household == uuid.Nil || a.Money >= allowance {
+			continue
+		}
+		if hi := w.householdIndex(a.Household); hi >= 0 {
+			w.Transfer(a.Household, a.ID, min(allowance-a.Money, w.Households[hi].Money), ledger.ReasonHousehold)
+		}
+	}
+}
+
+// means is what an agent can draw on: its own money and its share of the
+// household budget.
+func (w *World) means(a *agents.Agent) int64 {
+	hi := w.householdIndex(a.Household)
+	if hi < 0 {
+		return a.Money
+	}
+	h := &w.Households[hi]
+	return a.Money + h.Money/int64(max(len(h.Members), 1))
+}
+
+// pantryShort reports whether an agent's household needs food at home.
+func (w *World) pantryShort(a *agents.Agent) bool {
+	hi := w.householdIndex(a.Household)
+	if hi < 0 {
+		return false
+	}
+	h := &w.Households[hi]
+	stored := 0
+	for g, n := range h.Pantry {
+		if agents.IsFood(g) {
+			stored += n
+		}
+	}
+	return stored < pantryPerMember*len(h.Members)
+}
+
+// residents counts the members of the household living in a house.
+func (w *World) residents(home uuid.UUID) int {
+	if hi := w.householdAt(home); hi >= 0 {
+		return len(w.Households[hi].Members)
+	}
+	return 0
+}
+
+func (w *World) householdIndex(id uuid.UUID) int {
+	if id == uuid.Nil {
+		return -1
+	}
+	return slotOf(w.householdSlot, id)
+}
+
+func householdID(h *households.Household) uuid.UUID { return h.ID }
+
+func (w *World) householdAt(home uuid.UUID) int {
+	return slices.IndexFunc(w.Households, func(h households.Household) bool { return h.Home == home })
+}
+
+// publishHouseholds refreshes the snapshot served by the API. Tick calls it
+// last, so budgets and members are never more than a tick old.
+func (w *World) publishHouseholds() {
+	w.HouseholdDirectory.Publish(w.Households)
+}
@@ -0,0 +1,32 @@
+package world_test
+
+import (
+	"slices"
+	"testing"
+
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/scenario"
+
+	"github.com/google/uuid"
+)
+
+func TestHouseholdsPublishedEveryTick(t *testing.T) {
+	w := scenario.Build(small(5, 30))
+	for i := range 200 {
+		w.Tick(step)
+		if i == 100 {
+			// Leaving between population checks must show up at once.
+			w.RemoveAgent(w.Households[0].Members[0], events.ReasonDied)
+			w.Tick(step)
+		}
+		got := w.HouseholdDirectory.Households(uuid.Nil)
+		if len(got) != len(w.Households) {
+			t.Fatalf("tick %d: %d households published, want %d", w.Ticks, len(got), len(w.Households))
+		}
+		for j, h := range w.Households {
+			if got[j].ID != h.ID || got[j].Money != h.Money || !slices.Equal(got[j].Members, h.Members) {
+				t.Fatalf("tick %d: published %+v, want %+v", w.Ticks, got[j], h)
+			}
+		}
+	}
+}
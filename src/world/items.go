@@ -0,0 +1,148 @@
+package world
+
+import (
+	"errors"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/goods"
+	spatialhash "veatla/simulator/src/spatial-hash"
+	"veatla/simulator/src/zones"
+
+	"github.com/google/uuid"
+)
+
+// reach is how close an agent must be to a building or item to move goods.
+const reach = 2.5
+
+var (
+	ErrAgentNotFound    = errors.New("agent not found")
+	ErrBuildingNotFound = errors.New("building not found")
+	ErrItemNotFound     = errors.New("item not found")
+	ErrOutOfReach       = errors.New("out of reach")
+)
+
+// PickUp moves up to n units of g from the stock of a building or storage
+// zone into an agent's inventory and returns how many were moved.
+func (w *World) PickUp(agentID, buildingID uuid.UUID, g goods.Good, n int) (int, error) {
+	a, stock, err := w.agentAtStore(agentID, buildingID)
+	if err != nil {
+		return 0, err
+	}
+	n = min(n, stock.Count(g), a.Inventory.Room(g))
+	if n <= 0 {
+		return 0, nil
+	}
+	stock.Take(g, n)
+	a.Inventory.Add(g, n)
+	w.Emit(events.GoodsPickedUp{AgentID: agentID, SourceID: buildingID, Good: string(g), Qty: n})
+	return n, nil
+}
+
+// DropOff moves up to n units of g from an agent's inventory into the stock
+// of a building or storage zone and returns how many were moved.
+func (w *World) DropOff(agentID, buildingID uuid.UUID, g goods.Good, n int) (int, error) {
+	a, stock, err := w.agentAtStore(agentID, buildingID)
+	if err != nil {
+		return 0, err
+	}
+	n = a.Inventory.Take(g, n)
+	if n <= 0 {
+		return 0, nil
+	}
+	stock.Add(g, n)
+	w.Emit(events.GoodsDelivered{AgentID: agentID, BuildingID: buildingID, Good: string(g), Qty: n})
+	return n, nil
+}
+
+// PickUpItem moves as much of a ground item as fits into an agent's
+// inventory. Whatever does not fit stays on the ground.
+func (w *World) PickUpItem(agentID, itemID uuid.UUID) (int, error) {
+	ai := w.agentIndex(agentID)
+	if ai < 0 {
+		return 0, ErrAgentNotFound
+	}
+	ii := w.itemIndex(itemID)
+	if ii < 0 {
+		return 0, ErrItemNotFound
+	}
+	a, it := &w.Agents[ai], &w.Items[ii]
+	if agentBox(a).DistanceTo(it.X, it.Z) > reach {
+		return 0, ErrOutOfReach
+	}
+	n := a.Inventory.Add(it.Good, it.Qty)
+	if n <= 0 {
+		return 0, nil
+	}
+	good := it.Good
+	it.Qty -= n
+	if it.Qty == 0 {
+		w.Items = append(w.Items[:ii], w.Items[ii+1:]...)
+	}
+	w.Emit(events.GoodsPickedUp{AgentID: agentID, SourceID: itemID, Good: string(good), Qty: n})
+	return n, nil
+}
+
+// DropInventory leaves everything an agent carries on the ground at its
+// position, one item per good.
+func (w *World) DropInventory(agentID uuid.UUID) {
+	i := w.agentIndex(agentID)
+	if i < 0 {
+		return
+	}
+	a := &w.Agents[i]
+	stock := a.Inventory.Clear()
+	for _, g := range stock.Goods() {
+		it := goods.Item{ID: w.NewID(), X: a.X, Z: a.Z, Good: g, Qty: stock[g]}
+		w.Items = append(w.Items, it)
+		w.Emit(events.ItemDropped{ItemID: it.ID, Good: string(g), Qty: it.Qty, X: it.X, Z: it.Z})
+	}
+}
+
+// Interrupt abandons whatever an agent was carrying, e.g. when its task is
+// cancelled.
+func (w *World) Interrupt(agentID uuid.UUID) {
+	w.DropInventory(agentID)
+}
+
+func (w *World) agentAtStore(agentID, storeID uuid.UUID) (*agents.Agent, *goods.Stock, error) {
+	ai := w.agentIndex(agentID)
+	if ai < 0 {
+		return nil, nil, ErrAgentNotFound
+	}
+	stock, box, ok := w.store(storeID)
+	if !ok {
+		return nil, nil, ErrBuildingNotFound
+	}
+	a := &w.Agents[ai]
+	if box.DistanceTo(a.X, a.Z) > reach {
+		return nil, nil, ErrOutOfReach
+	}
+	return a, stock, nil
+}
+
+// store returns the stock and footprint of a building or storage zone.
+func (w *World) store(id uuid.UUID) (*goods.Stock, spatialhash.AABB, bool) {
+	if i := w.buildingIndex(id); i >= 0 {
+		b := &w.Buildings[i]
+		return &b.Stock, spatialhash.AABB{MinX: b.MinX, MinZ: b.MinZ, MaxX: b.MaxX, MaxZ: b.MaxZ}, true
+	}
+	if i := w.zoneIndex(id); i >= 0 && w.Zones[i].Kind == zones.KindStorage {
+		z := &w.Zones[i]
+		return &z.Stock, spatialhash.AABB{MinX: z.MinX, MinZ: z.MinZ, MaxX: z.MaxX, MaxZ: z.MaxZ}, true
+	}
+	return nil, spatialhash.AABB{}, false
+}
+
+func (w *World) buildingIndex(id uuid.UUID) int {
+	return slotOf(w.buildingSlot, id)
+}
+
+func (w *World) itemIndex(id uuid.UUID) int {
+	for i := range w.Items {
+		if w.Items[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
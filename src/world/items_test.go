@@ -0,0 +1,56 @@
+package world
+
+import (
+	"errors"
+	"testing"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/goods"
+)
+
+func TestPickUpAndDropOff(t *testing.T) {
+	w, a, store, _ := builderWorld(t)
+	w.Buildings[w.buildingIndex(store)].Stock = goods.Stock{goods.Wood: 4}
+	if _, err := w.PickUp(a.ID, store, goods.Wood, 2); !errors.Is(err, ErrOutOfReach) {
+		t.Fatalf("picking up from afar: err = %v, want %v", err, ErrOutOfReach)
+	}
+
+	a.X, a.Z, _ = w.storeApproach(store)
+	if n, err := w.PickUp(a.ID, store, goods.Wood, 10); err != nil || n != 4 {
+		t.Fatalf("picked up %d, %v; want all 4", n, err)
+	}
+	if n, err := w.DropOff(a.ID, store, goods.Wood, 1); err != nil || n != 1 {
+		t.Fatalf("dropped off %d, %v; want 1", n, err)
+	}
+	if a.Inventory.Count(goods.Wood) != 3 || w.Buildings[w.buildingIndex(store)].Stock.Count(goods.Wood) != 1 {
+		t.Fatalf("agent has %d wood, store %d", a.Inventory.Count(goods.Wood), w.Buildings[w.buildingIndex(store)].Stock.Count(goods.Wood))
+	}
+}
+
+func TestDeadAgentsDropWhatTheyCarry(t *testing.T) {
+	w, a, _, _ := builderWorld(t)
+	a.Inventory.Add(goods.Wood, 2)
+	a.Inventory.Add(goods.Stone, 1)
+	x, z, id := a.X, a.Z, a.ID
+	w.RemoveAgent(id, events.ReasonDied)
+
+	if len(w.Items) != 2 {
+		t.Fatalf("%d items on the ground, want 2", len(w.Items))
+	}
+	for _, it := range w.Items {
+		if it.X != x || it.Z != z {
+			t.Errorf("%s dropped at (%v, %v), want (%v, %v)", it.Good, it.X, it.Z, x, z)
+		}
+	}
+
+	// Someone else can pick them up again.
+	w.AddAgent(agents.CreateFromArchetypeAt(w, mustArchetype(t, w.Archetypes), x, z))
+	b := &w.Agents[0]
+	if n, err := w.PickUpItem(b.ID, w.Items[0].ID); err != nil || n == 0 {
+		t.Fatalf("picked up %d, %v", n, err)
+	}
+	if len(w.Items) != 1 {
+		t.Fatalf("%d items left, want 1", len(w.Items))
+	}
+}
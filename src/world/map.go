@@ -0,0 +1,21 @@
+package world
+
+import "veatla/simulator/src/generator"
+
+// LoadMap attaches a generated map to the world and places its obstacles
+// and resource nodes.
+func (w *World) LoadMap(m generator.Map) {
+	w.Map = &m
+	for _, o := range m.Obstacles {
+		w.AddObstacle(o)
+	}
+	for _, n := range m.Nodes {
+		w.AddNode(n)
+	}
+	w.fortify(m.Castle)
+}
+
+// GenerateMap generates a map from the world seed and loads it.
+func (w *World) GenerateMap() {
+	w.LoadMap(generator.Generate(w.Seed, w.Width, w.Height))
+}
@@ -0,0 +1,210 @@
+package world
+
+import (
+	"math"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/goods"
+	"veatla/simulator/src/ledger"
+	"veatla/simulator/src/market"
+	spatialhash "veatla/simulator/src/spatial-hash"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// priceHistory is the number of price points kept for the API.
+	priceHistory = 4096
+	// marketRound is how many ticks pass between order rounds. Orders live
+	// for one round and are replaced by the next.
+	marketRound = 20
+	// marketRange is how far an agent may be from a market to trade there.
+	marketRange = 25
+	// marketTarget is the stock per good a market tries to hold.
+	marketTarget = 10
+	// markup and discount are applied to the reference price by markets
+	// selling and agents selling respectively.
+	markup   = 1.1
+	discount = 0.9
+)
+
+// holder is anything that can give and receive goods.
+type holder interface {
+	Count(g goods.Good) int
+	Room(g goods.Good) int
+	Add(g goods.Good, n int) int
+	Take(g goods.Good, n int) int
+}
+
+// buildingStock adapts a building's unbounded stock to holder.
+type buildingStock struct{ goods.Stock }
+
+func (s buildingStock) Room(goods.Good) int { return math.MaxInt }
+
+func (s buildingStock) Add(g goods.Good, n int) int {
+	s.Stock.Add(g, n)
+	return n
+}
+
+// marketTick runs an order round every marketRound ticks and records
+// prices every tick.
+func (w *World) marketTick() {
+	if len(w.Markets) == 0 {
+		return
+	}
+	if w.Ticks%marketRound == 0 {
+		w.marketRound()
+	}
+	for _, m := range w.Markets {
+		w.PriceHistory.Record(w.Ticks, m)
+	}
+}
+
+func (w *World) marketRound() {
+	expires := w.Ticks + marketRound
+
+	for _, m := range w.Markets {
+		if i := w.buildingIndex(m.ID); i >= 0 {
+			w.marketOrders(m, &w.Buildings[i], expires)
+		}
+	}
+
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if m := w.nearestMarket(a); m != nil {
+			w.agentOrders(m, a, expires)
+		}
+		if a.Hungry() {
+			a.Eat()
+		}
+		a.Enjoy()
+	}
+
+	for _, m := range w.Markets {
+		m.Adjust(w.Ticks)
+	}
+}
+
+// marketOrders lets the market building sell its stock and restock goods
+// it is short of.
+func (w *World) marketOrders(m *market.Market, b *constructions.Building, expires int) {
+	m.Cancel(b.ID)
+	for _, g := range goods.All() {
+		price := m.Price(g)
+		if n := b.Stock.Count(g); n > 0 {
+			m.Place(b.ID, market.Sell, g, n, max(int64(math.Ceil(price*markup)), 1), expires, w.settle(m))
+		}
+		want := marketTarget - b.Stock.Count(g)
+		limit := int64(price * discount)
+		if want > 0 && limit > 0 && b.Money >= limit*int64(want) {
+			m.Place(b.ID, market.Buy, g, want, limit, expires, w.settle(m))
+		}
+	}
+}
+
+// agentOrders has a hungry agent, or one shopping for a bare pantry, bid
+// for bread, a well-off agent bid for cloth, and lets agents that are not
+// hauling for work sell what they carry.
+func (w *World) agentOrders(m *market.Market, a *agents.Agent, expires int) {
+	m.Cancel(a.ID)
+	shopping := a.Schedule.Current == agents.ActivityMarket && w.pantryShort(a)
+	if (a.Hungry() || shopping) && a.Money > 0 && a.Inventory.Count(goods.Bread) == 0 {
+		limit := min(a.Money, int64(math.Ceil(m.Price(goods.Bread)*(1+a.Needs.Hunger))))
+		m.Place(a.ID, market.Buy, goods.Bread, 1, limit, expires, w.settle(m))
+	}
+	if w.means(a) >= w.Economy.LuxuryAbove && a.Inventory.Count(goods.Cloth) == 0 {
+		limit := int64(math.Ceil(m.Price(goods.Cloth) * markup))
+		m.Place(a.ID, market.Buy, goods.Cloth, 1, limit, expires, w.settle(m))
+	}
+	if a.Behaviour == agents.BehaviourHaul || a.Behaviour == agents.BehaviourBuild || a.Behaviour == agents.BehaviourFarm ||
+		a.Behaviour == agents.BehaviourGather {
+		return
+	}
+	for _, g := range a.Inventory.Items.Goods() {
+		if agents.IsFood(g) || agents.IsLuxury(g) {
+			continue
+		}
+		limit := max(int64(m.Price(g)*discount), 1)
+		m.Place(a.ID, market.Sell, g, a.Inventory.Count(g), limit, expires, w.settle(m))
+	}
+}
+
+// settle returns how fills at m are settled: goods and money change hands
+// and the seller pays sales tax. A party that no longer has the goods, the
+// money or the room is reported so the book can drop its order.
+func (w *World) settle(m *market.Market) market.Settle {
+	return func(f market.Fill) (bool, bool) {
+		buyer, seller := w.holder(f.Buyer), w.holder(f.Seller)
+		cost := f.Price * int64(f.Qty)
+		funds := w.balance(f.Buyer)
+		buyerOK := buyer != nil && funds != nil && *funds >= cost && buyer.Room(f.Good) >= f.Qty
+		sellerOK := seller != nil && seller.Count(f.Good) >= f.Qty
+		if !buyerOK || !sellerOK {
+			return buyerOK, sellerOK
+		}
+		if w.Transfer(f.Buyer, f.Seller, cost, ledger.ReasonTrade) != nil {
+			return false, true
+		}
+		seller.Take(f.Good, f.Qty)
+		buyer.Add(f.Good, f.Qty)
+		w.Castle.Current.SalesTax += w.levy(f.Seller, cost, w.Castle.Policy.SalesTax, ledger.ReasonSalesTax)
+		w.Emit(events.TradeExecuted{
+			MarketID: m.ID,
+			Good:     string(f.Good),
+			Buyer:    f.Buyer,
+			Seller:   f.Seller,
+			Qty:      f.Qty,
+			Price:    f.Price,
+		})
+		return true, true
+	}
+}
+
+// holder returns the goods of an agent or building.
+func (w *World) holder(id uuid.UUID) holder {
+	if i := w.agentIndex(id); i >= 0 {
+		return &w.Agents[i].Inventory
+	}
+	if i := w.buildingIndex(id); i >= 0 {
+		return buildingStock{w.Buildings[i].Stock}
+	}
+	return nil
+}
+
+// nearestMarket returns the closest market within marketRange of the agent
+// that it knows of.
+func (w *World) nearestMarket(a *agents.Agent) *market.Market {
+	var best *market.Market
+	bestDist := float64(marketRange)
+	for _, m := range w.Markets {
+		i := w.buildingIndex(m.ID)
+		if i < 0 || !a.Memory.Knows(m.ID) {
+			continue
+		}
+		b := w.Buildings[i]
+		box := spatialhash.AABB{MinX: b.MinX, MinZ: b.MinZ, MaxX: b.MaxX, MaxZ: b.MaxZ}
+		if d := box.DistanceTo(a.X, a.Z); d <= bestDist {
+			best, bestDist = m, d
+		}
+	}
+	return best
+}
+
+// AveragePrices returns each good's reference price averaged over markets.
+func (w *World) AveragePrices() map[goods.Good]float64 {
+	out := map[goods.Good]float64{}
+	if len(w.Markets) == 0 {
+		return out
+	}
+	for _, m := range w.Markets {
+		for g, p := range m.Prices() {
+			out[g] += p
+		}
+	}
+	for g := range out {
+		out[g] /= float64(len(w.Markets))
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package world
+
+import (
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	spatialhash "veatla/simulator/src/spatial-hash"
+	"veatla/simulator/src/zones"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// rememberEvery spreads remembering over ticks: each agent commits what
+	// it sees to memory once every rememberEvery ticks.
+	rememberEvery = 10
+	// forgetDays is how long a place goes unseen before it is forgotten.
+	forgetDays = 5.0
+)
+
+// memoryTick has a share of the agents remember what they see, and fades
+// what they have not seen for a while.
+func (w *World) memoryTick(dt time.Duration) {
+	fade := rememberEvery * float64(dt) / float64(w.Calendar.DayLength) / forgetDays
+	var buf []uuid.UUID
+	for i := range w.Agents {
+		if (i+w.Ticks)%rememberEvery != 0 {
+			continue
+		}
+		a := &w.Agents[i]
+		a.Memory.Fade(fade)
+		buf = w.remember(a, buf)
+	}
+}
+
+// remember records the buildings, resource nodes, construction sites,
+// storage zones and farm fields an agent sees. buf is scratch space and is
+// returned for reuse.
+func (w *World) remember(a *agents.Agent, buf []uuid.UUID) []uuid.UUID {
+	for _, id := range a.Perception.Objects {
+		if bi := w.buildingIndex(id); bi >= 0 {
+			b := &w.Buildings[bi]
+			x, z := (b.MinX+b.MaxX)/2, (b.MinZ+b.MaxZ)/2
+			a.Memory.See(b.ID, placeKind(b), x, z)
+		} else if ni := w.nodeIndex(id); ni >= 0 {
+			n := &w.Nodes[ni]
+			a.Memory.See(n.ID, agents.PlaceNode, (n.MinX+n.MaxX)/2, (n.MinZ+n.MaxZ)/2)
+		}
+	}
+	ex, ez := a.Eye()
+	r := a.ViewRadius()
+	view := spatialhash.AABB{MinX: ex - r, MinZ: ez - r, MaxX: ex + r, MaxZ: ez + r}
+	// Sites only enter the grid once scaffolded, so they are checked one by
+	// one, like gates.
+	for i := range w.Sites {
+		s := &w.Sites[i]
+		if box := siteBox(s); box.Overlaps(view) && w.sees(a, box, s.ID) {
+			a.Memory.See(s.ID, agents.PlaceSite, (box.MinX+box.MaxX)/2, (box.MinZ+box.MaxZ)/2)
+		}
+	}
+	buf = w.ZoneIndex.Overlapping(buf[:0], view)
+	for _, id := range buf {
+		zi := w.zoneIndex(id)
+		if zi < 0 {
+			continue
+		}
+		kind := agents.PlaceStore
+		switch w.Zones[zi].Kind {
+		case zones.KindStorage:
+		case zones.KindFarm:
+			kind = agents.PlaceField
+		default:
+			continue
+		}
+		if !w.sees(a, zoneBox(&w.Zones[zi]), uuid.Nil) {
+			continue
+		}
+		x, z := w.Zones[zi].Anchor()
+		a.Memory.See(id, kind, x, z)
+	}
+	return buf
+}
+
+// witnessDeath has every agent that sees a death remember the spot as
+// dangerous.
+func (w *World) witnessDeath(dead *agents.Agent) {
+	for i := range w.Agents {
+		if a := &w.Agents[i]; a.Perception.SeesAgent(dead.ID) {
+			a.Memory.See(dead.ID, agents.PlaceDanger, dead.X, dead.Z)
+		}
+	}
+}
+
+func placeKind(b *constructions.Building) agents.PlaceKind {
+	switch b.Kind {
+	case constructions.BuildingStockpile:
+		return agents.PlaceStore
+	case constructions.BuildingMarket:
+		return agents.PlaceMarket
+	case constructions.BuildingHouse:
+		return agents.PlaceHouse
+	default:
+		return agents.PlaceWorkplace
+	}
+}
@@ -0,0 +1,19 @@
+package world
+
+import (
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+)
+
+// AddObstacle appends an obstacle and inserts its footprint into the grid.
+func (w *World) AddObstacle(o constructions.Obstacle) {
+	w.Obstacles = append(w.Obstacles, o)
+	w.Grid.Insert(o.ID, o.MinX, o.MinZ, o.MaxX, o.MaxZ, true)
+	w.Emit(events.ObstaclePlaced{
+		ObstacleID: o.ID,
+		MinX:       o.MinX,
+		MinZ:       o.MinZ,
+		MaxX:       o.MaxX,
+		MaxZ:       o.MaxZ,
+	})
+}
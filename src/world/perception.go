@@ -0,0 +1,101 @@
+package world
+
+import (
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	spatialhash "veatla/simulator/src/spatial-hash"
+	"veatla/simulator/src/utils"
+
+	"github.com/google/uuid"
+)
+
+// perceptionTick refreshes what every agent sees. It runs once a tick,
+// before agents decide, so every behaviour sees the same sets all tick.
+// Agents look in parallel: the grid is only read and each agent only writes
+// its own perception.
+func (w *World) perceptionTick() {
+	parallelChunks(len(w.Agents), func(_, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			a := &w.Agents[i]
+			a.FaceMovement()
+			w.look(a)
+		}
+	})
+}
+
+// look gathers the agents, obstacles and gates within an agent's view radius
+// and field of view that nothing hides.
+func (w *World) look(a *agents.Agent) {
+	p := &a.Perception
+	p.Tick = w.Ticks
+	ex, ez := a.Eye()
+	r := a.ViewRadius()
+
+	p.Agents = w.Grid.QueryRadius(p.Agents[:0], ex, ez, r, spatialhash.KindAgent)
+	seen := p.Agents[:0]
+	for _, id := range p.Agents {
+		box, ok := w.Grid.Bounds(id)
+		if !ok || id == a.ID {
+			continue
+		}
+		// An agent's eye is the centre of its footprint.
+		x, z := (box.MinX+box.MaxX)/2, (box.MinZ+box.MaxZ)/2
+		if _, _, blocked := w.raycast(ex, ez, x, z); a.InView(x, z) && !blocked {
+			seen = append(seen, id)
+		}
+	}
+	p.Agents = seen
+
+	p.Objects = w.Grid.QueryRadius(p.Objects[:0], ex, ez, r, spatialhash.KindObstacle)
+	seen = p.Objects[:0]
+	for _, id := range p.Objects {
+		if box, ok := w.Grid.Bounds(id); ok && w.sees(a, box, id) {
+			seen = append(seen, id)
+		}
+	}
+	// Gates are not in the grid, so they are checked one by one.
+	for i := range w.Fortifications {
+		g := &w.Fortifications[i]
+		if g.Kind != constructions.FortGate {
+			continue
+		}
+		box := fortBox(g)
+		if box.DistanceTo(ex, ez) <= r && w.sees(a, box, g.ID) {
+			seen = append(seen, g.ID)
+		}
+	}
+	p.Objects = seen
+}
+
+// sees reports whether an agent sees the point of box nearest to it. An
+// obstacle hides everything behind it but not itself, so id is the
+// obstacle the box belongs to, or uuid.Nil for areas such as zones.
+func (w *World) sees(a *agents.Agent, box spatialhash.AABB, id uuid.UUID) bool {
+	ex, ez := a.Eye()
+	x, z := utils.Clamp(ex, box.MinX, box.MaxX), utils.Clamp(ez, box.MinZ, box.MaxZ)
+	if !a.InView(x, z) {
+		return false
+	}
+	hit, _, blocked := w.raycast(ex, ez, x, z)
+	return !blocked || hit == id
+}
+
+// raycast is Grid.Raycast with closed gates, which block sight even for the
+// factions they let through.
+func (w *World) raycast(x0, z0, x1, z1 float64) (uuid.UUID, float64, bool) {
+	id, t, hit := w.Grid.Raycast(x0, z0, x1, z1)
+	for i := range w.Fortifications {
+		g := &w.Fortifications[i]
+		if g.Kind != constructions.FortGate || g.Open {
+			continue
+		}
+		if gt, ok := fortBox(g).Crosses(x0, z0, x1, z1); ok && (!hit || gt < t) {
+			id, t, hit = g.ID, gt, true
+		}
+	}
+	return id, t, hit
+}
+
+func fortBox(f *constructions.Fortification) spatialhash.AABB {
+	return spatialhash.AABB{MinX: f.MinX, MinZ: f.MinZ, MaxX: f.MaxX, MaxZ: f.MaxZ}
+}
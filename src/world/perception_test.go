@@ -0,0 +1,69 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/castle"
+	"veatla/simulator/src/constructions"
+)
+
+// gateWorld puts two agents ten units apart, facing each other across a gate
+// that only the castle faction may pass, and a third behind the first.
+func gateWorld(t *testing.T) (*World, constructions.Fortification) {
+	t.Helper()
+	w := NewWorld(1, 40, 40)
+	g := constructions.CreateFortification(w.NewID(), constructions.FortGate, 19, 10, 21, 30)
+	g.Factions = []string{castle.Faction}
+	w.AddFortification(g)
+	def := mustArchetype(t, w.Archetypes)
+	for _, x := range []float64{14.5, 24.5, 4.5} {
+		w.AddAgent(agents.CreateFromArchetypeAt(&w, def, x, 19.5))
+	}
+	w.Agents[0].Perception.Heading = 0
+	w.Agents[1].Perception.Heading = math.Pi
+	w.Agents[2].Perception.Heading = 0
+	return &w, g
+}
+
+func TestClosedGateBlocksSight(t *testing.T) {
+	w, g := gateWorld(t)
+	a, b := &w.Agents[0], &w.Agents[1]
+
+	for _, open := range []bool{true, false, true} {
+		if err := w.SetGate(g.ID, open); err != nil {
+			t.Fatal(err)
+		}
+		w.look(a)
+		w.look(b)
+		if a.Perception.SeesAgent(b.ID) != open || b.Perception.SeesAgent(a.ID) != open {
+			t.Errorf("gate open %v: a sees b %v, b sees a %v", open, a.Perception.SeesAgent(b.ID), b.Perception.SeesAgent(a.ID))
+		}
+		if !a.Perception.SeesObject(g.ID) {
+			t.Errorf("gate open %v: a does not see the gate itself", open)
+		}
+	}
+}
+
+func TestSightIsAConeAndObstaclesHide(t *testing.T) {
+	w, g := gateWorld(t)
+	a, b, c := &w.Agents[0], &w.Agents[1], &w.Agents[2]
+	w.look(a)
+	w.look(c)
+	if a.Perception.SeesAgent(c.ID) {
+		t.Error("a sees c behind its back")
+	}
+	if !c.Perception.SeesAgent(a.ID) {
+		t.Error("c does not see a in front of it")
+	}
+
+	// A wall between a and b hides b but is seen itself.
+	w.SetGate(g.ID, true)
+	wall := constructions.CreateObstacle(w.NewID(), 17, 15, 18, 25)
+	w.AddObstacle(wall)
+	w.look(a)
+	if a.Perception.SeesAgent(b.ID) || !a.Perception.SeesObject(wall.ID) {
+		t.Errorf("with a wall between: sees b %v, sees the wall %v", a.Perception.SeesAgent(b.ID), a.Perception.SeesObject(wall.ID))
+	}
+}
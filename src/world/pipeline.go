@@ -0,0 +1,180 @@
+package world
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/utils"
+	worldQuery "veatla/simulator/src/world-query"
+
+	"github.com/google/uuid"
+)
+
+// chunkSize is the number of agents one worker job covers. It is fixed rather
+// than derived from the worker count so per-chunk RNG streams, and therefore
+// results, are the same on any machine.
+const chunkSize = 256
+
+// pipelineBuffers holds per-tick scratch space, reused between ticks.
+type pipelineBuffers struct {
+	proposals []agents.Agent
+	changed   []bool
+	views     []chunkView
+}
+
+// chunkView is the read-only world view handed to the agents of one chunk.
+// Randomness and IDs come from a stream seeded by world seed, tick and chunk,
+// and emitted events are held until commit.
+type chunkView struct {
+	w      *World
+	seed   int64
+	rng    *rand.Rand
+	events []events.Event
+}
+
+func (v *chunkView) IsPointBlocked(x, z float64, who worldQuery.Passer) bool {
+	return v.w.IsPointBlocked(x, z, who)
+}
+func (v *chunkView) GetWorldSeed() int64 { return v.w.Seed }
+func (v *chunkView) GetBoundaries() (float64, float64) {
+	return v.w.Width, v.w.Height
+}
+func (v *chunkView) Emit(e events.Event)           { v.events = append(v.events, e) }
+func (v *chunkView) DayLength() time.Duration      { return v.w.Calendar.DayLength }
+func (v *chunkView) Now() calendar.Time            { return v.w.Now() }
+func (v *chunkView) MoveCost(x, z float64) float64 { return v.w.MoveCost(x, z) }
+
+func (v *chunkView) random() *rand.Rand {
+	if v.rng == nil {
+		v.rng = rand.New(rand.NewSource(v.seed))
+	}
+	return v.rng
+}
+
+func (v *chunkView) RandomFloat() float64 { return v.random().Float64() }
+
+func (v *chunkView) NewID() uuid.UUID {
+	id, err := uuid.NewRandomFromReader(v.random())
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func (w *World) tickBuffers(n int) *pipelineBuffers {
+	b := &w.pipeline
+	if cap(b.proposals) < n {
+		b.proposals = make([]agents.Agent, n)
+		b.changed = make([]bool, n)
+	}
+	b.proposals = b.proposals[:n]
+	b.changed = b.changed[:n]
+
+	chunks := (n + chunkSize - 1) / chunkSize
+	if cap(b.views) < chunks {
+		b.views = make([]chunkView, chunks)
+	}
+	b.views = b.views[:chunks]
+	for c := range b.views {
+		b.views[c] = chunkView{
+			w:      w,
+			seed:   w.Seed ^ int64(w.Ticks)<<20 ^ int64(c),
+			events: b.views[c].events[:0],
+		}
+	}
+	return b
+}
+
+// parallelChunks runs fn over every chunk of n agents on a fixed pool of
+// GOMAXPROCS workers.
+func parallelChunks(n int, fn func(chunk, lo, hi int)) {
+	chunks := (n + chunkSize - 1) / chunkSize
+	workers := min(runtime.GOMAXPROCS(0), chunks)
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for {
+				c := int(next.Add(1) - 1)
+				if c >= chunks {
+					return
+				}
+				lo := c * chunkSize
+				fn(c, lo, min(lo+chunkSize, n))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// decide has every agent plan its next state on a copy of itself. The copy
+// is shallow: its Memory, Inventory and Skills maps are still the live
+// agent's, so code run during decide must not write them. Eating, trading
+// and remembering happen in the world's tick phases instead.
+func (w *World) decide(b *pipelineBuffers, dt time.Duration) {
+	parallelChunks(len(b.proposals), func(c, lo, hi int) {
+		view := &b.views[c]
+		for i := lo; i < hi; i++ {
+			p := &b.proposals[i]
+			*p = w.Agents[i]
+			b.changed[i] = p.Tick(dt, view)
+		}
+	})
+}
+
+func (w *World) resolve(b *pipelineBuffers) {
+	for i := range b.proposals {
+		p := &b.proposals[i]
+		cur := &w.Agents[i]
+		if p.X == cur.X && p.Z == cur.Z {
+			continue
+		}
+		if w.IsPointBlocked(p.X, p.Z, p.Passer()) {
+			p.X, p.Z = cur.X, cur.Z
+			p.VX, p.VZ = 0, 0
+			b.changed[i] = false
+			continue
+		}
+		p.X = utils.Clamp(p.X, 0, w.Width)
+		p.Z = utils.Clamp(p.Z, 0, w.Height)
+	}
+}
+
+func (w *World) commit(b *pipelineBuffers) []agents.Agent {
+	var changedAgents []agents.Agent
+	var entered []uuid.UUID
+	for i := range b.proposals {
+		before := agentBox(&w.Agents[i])
+		wasInside := w.insideCastle(w.Agents[i].X, w.Agents[i].Z)
+		w.Agents[i] = b.proposals[i]
+		a := &w.Agents[i]
+		if !wasInside && w.insideCastle(a.X, a.Z) {
+			entered = append(entered, a.ID)
+		}
+
+		if box := agentBox(a); box != before {
+			w.Grid.Move(a.ID, before, box)
+		}
+		if b.changed[i] {
+			changedAgents = append(changedAgents, *a)
+		}
+	}
+	w.lastTick.AgentsMoved = len(changedAgents)
+	w.chargeTolls(entered)
+
+	for c := range b.views {
+		for _, e := range b.views[c].events {
+			w.Emit(e)
+		}
+	}
+	return changedAgents
+}
@@ -0,0 +1,75 @@
+package world_test
+
+import (
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"slices"
+	"testing"
+
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/scenario"
+
+	"github.com/google/uuid"
+)
+
+// fingerprint runs a seed for some ticks and hashes the agents' state and
+// every event published.
+func fingerprint(seed int64, agents, ticks int) uint64 {
+	w := scenario.Build(small(seed, agents))
+	h := fnv.New64a()
+	w.Events.Subscribe(func(r events.Record) {
+		fmt.Fprintf(h, "%d %T %+v\n", r.Tick, r.Event, r.Event)
+	})
+	for range ticks {
+		for _, a := range w.Tick(step) {
+			fmt.Fprintf(h, "%v %v %v %v %v\n", a.ID, a.X, a.Z, a.VX, a.VZ)
+		}
+	}
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		fmt.Fprintf(h, "%v %v %v %v %v\n", a.ID, a.X, a.Z, a.Money, a.Inventory.Items)
+	}
+	return h.Sum64()
+}
+
+func TestSameSeedAnyGOMAXPROCS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs several hundred agents")
+	}
+	// More agents than one chunk, so decide and perception run in parallel.
+	const agents, ticks = 300, 60
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	var want uint64
+	for i, procs := range []int{1, 2, 8} {
+		runtime.GOMAXPROCS(procs)
+		got := fingerprint(42, agents, ticks)
+		if i == 0 {
+			want = got
+		} else if got != want {
+			t.Errorf("GOMAXPROCS=%d: fingerprint %x, want %x", procs, got, want)
+		}
+	}
+}
+
+func TestSameSeedSameIDs(t *testing.T) {
+	ids := func() []uuid.UUID {
+		w := scenario.Build(small(8, 5))
+		var out []uuid.UUID
+		for _, b := range w.Buildings {
+			out = append(out, b.ID)
+		}
+		for _, z := range w.Zones {
+			out = append(out, z.ID)
+		}
+		for _, f := range w.Fortifications {
+			out = append(out, f.ID)
+		}
+		return out
+	}
+	a, b := ids(), ids()
+	if len(a) == 0 || !slices.Equal(a, b) {
+		t.Fatalf("IDs differ between builds of one seed:\n%v\n%v", a, b)
+	}
+}
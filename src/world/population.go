@@ -0,0 +1,184 @@
+package world
+
+import (
+	"math/rand"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	worldQuery "veatla/simulator/src/world-query"
+
+	"github.com/google/uuid"
+)
+
+// PopulationRules controls how population responds to housing and prosperity.
+// Rates are per simulated day and scaled by Interval.
+type PopulationRules struct {
+	// Interval is how often the rules are evaluated, in simulated time.
+	Interval time.Duration
+	// HomelessCapacity is the number of agents allowed on top of housing.
+	HomelessCapacity int
+	// ImmigrationRate is the expected immigrants per day at full prosperity.
+	ImmigrationRate float64
+	// BirthRate is the expected births per house per day at full prosperity.
+	BirthRate float64
+	// EmigrationRate is the chance per day that an unhappy agent leaves.
+	EmigrationRate float64
+	// UnhappyBelow is the happiness under which agents consider leaving.
+	UnhappyBelow float64
+	// ProsperousAbove is the average happiness needed to attract immigrants.
+	ProsperousAbove float64
+	MinLifespan     time.Duration
+	MaxLifespan     time.Duration
+}
+
+func DefaultPopulationRules() PopulationRules {
+	return PopulationRules{
+		Interval:         calendar.DefaultDayLength / 24,
+		HomelessCapacity: 4,
+		ImmigrationRate:  2,
+		BirthRate:        0.1,
+		EmigrationRate:   0.5,
+		UnhappyBelow:     0.3,
+		ProsperousAbove:  0.5,
+		MinLifespan:      60 * calendar.DefaultDayLength,
+		MaxLifespan:      80 * calendar.DefaultDayLength,
+	}
+}
+
+func (r PopulationRules) lifespan(rng *rand.Rand) time.Duration {
+	if r.MaxLifespan <= r.MinLifespan {
+		return r.MinLifespan
+	}
+	return r.MinLifespan + time.Duration(rng.Int63n(int64(r.MaxLifespan-r.MinLifespan)))
+}
+
+// PopulationCap is the most agents the world supports right now.
+func (w *World) PopulationCap() int {
+	return w.HousingCapacity() + w.Population.HomelessCapacity
+}
+
+// Prosperity is the average agent happiness, or 1 for an empty world.
+func (w *World) Prosperity() float64 {
+	if len(w.Agents) == 0 {
+		return 1
+	}
+	sum := 0.0
+	for i := range w.Agents {
+		sum += w.Agents[i].Happiness
+	}
+	return sum / float64(len(w.Agents))
+}
+
+func (w *World) populationTick(dt time.Duration) {
+	rules := w.Population
+	if rules.Interval <= 0 {
+		return
+	}
+	w.sincePop += dt
+	if w.sincePop < rules.Interval {
+		return
+	}
+	w.sincePop -= rules.Interval
+	frac := float64(rules.Interval) / float64(w.Calendar.DayLength)
+
+	// Collect first so removal does not shift the slice under the loop.
+	type leaver struct {
+		id     uuid.UUID
+		reason string
+	}
+	var leaving []leaver
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		switch {
+		case a.Lifespan > 0 && a.Age >= a.Lifespan:
+			leaving = append(leaving, leaver{a.ID, events.ReasonDied})
+		case a.Happiness < rules.UnhappyBelow && w.rng.Float64() < rules.EmigrationRate*frac:
+			leaving = append(leaving, leaver{a.ID, events.ReasonEmigrated})
+		}
+	}
+	for _, l := range leaving {
+		w.RemoveAgent(l.id, l.reason)
+	}
+	w.settleHomeless()
+
+	prosperity := w.Prosperity()
+	free := w.PopulationCap() - len(w.Agents)
+
+	if free > 0 && prosperity >= rules.ProsperousAbove && w.rng.Float64() < rules.ImmigrationRate*frac*prosperity {
+		if x, z, ok := w.edgeSpawnPoint(); ok {
+			w.SpawnAgent(agents.CreateFromArchetypeAt(w, w.PickArchetype(), x, z), events.ReasonImmigrated)
+			free--
+		}
+	}
+
+	// Children are born to households of two or more with room for them.
+	for hi := range w.Households {
+		if free <= 0 {
+			break
+		}
+		h := &w.Households[hi]
+		bi := w.buildingIndex(h.Home)
+		if bi < 0 || len(h.Members) < 2 || len(h.Members) >= w.Buildings[bi].Capacity ||
+			w.rng.Float64() >= rules.BirthRate*frac*prosperity {
+			continue
+		}
+		if x, z, ok := w.doorPoint(w.Buildings[bi].Obstacle); ok {
+			def, _ := w.Archetypes.Get(agents.DefaultArchetype)
+			child := agents.CreateFromArchetypeAt(w, def, x, z)
+			// Children are born without savings; money only enters by minting
+			// or immigration.
+			child.Money = 0
+			w.SpawnAgent(child, events.ReasonBorn)
+			w.joinHousehold(&w.Agents[len(w.Agents)-1], hi)
+			free--
+		}
+	}
+}
+
+// edgeSpawnPoint picks a free point on a random edge of the map.
+func (w *World) edgeSpawnPoint() (float64, float64, bool) {
+	const attempts = 20
+	const inset = 0.5
+	for range attempts {
+		t := w.rng.Float64()
+		var x, z float64
+		switch w.rng.Intn(4) {
+		case 0:
+			x, z = t*w.Width, inset
+		case 1:
+			x, z = t*w.Width, w.Height-inset
+		case 2:
+			x, z = inset, t*w.Height
+		default:
+			x, z = w.Width-inset, t*w.Height
+		}
+		if !w.IsPointBlocked(x, z, worldQuery.Passer{}) {
+			return x, z, true
+		}
+	}
+	return 0, 0, false
+}
+
+// doorPoint finds a free point just outside a footprint, trying the south
+// side first.
+func (w *World) doorPoint(o constructions.Obstacle) (float64, float64, bool) {
+	const gap = 1.0
+	cx, cz := (o.MinX+o.MaxX)/2, (o.MinZ+o.MaxZ)/2
+	for _, p := range [][2]float64{
+		{cx, o.MaxZ + gap},
+		{cx, o.MinZ - gap},
+		{o.MinX - gap, cz},
+		{o.MaxX + gap, cz},
+	} {
+		if p[0] < 0 || p[1] < 0 || p[0] > w.Width || p[1] > w.Height {
+			continue
+		}
+		if !w.IsPointBlocked(p[0], p[1], worldQuery.Passer{}) {
+			return p[0], p[1], true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,58 @@
+package world_test
+
+import (
+	"testing"
+
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/scenario"
+)
+
+func TestOldAgentsDie(t *testing.T) {
+	w := scenario.Build(small(6, 10))
+	old := w.Agents[3]
+	w.Agents[3].Age = old.Lifespan
+	var despawned []events.AgentDespawned
+	w.Events.Subscribe(func(r events.Record) {
+		if e, ok := r.Event.(events.AgentDespawned); ok {
+			despawned = append(despawned, e)
+		}
+	})
+
+	for range int(w.Population.Interval/step) + 1 {
+		w.Tick(step)
+	}
+	if len(despawned) != 1 || despawned[0].AgentID != old.ID || despawned[0].Reason != events.ReasonDied {
+		t.Fatalf("despawned = %+v, want agent %s dead", despawned, old.ID)
+	}
+	if _, ok := w.Grid.Bounds(old.ID); ok {
+		t.Error("dead agent is still in the spatial hash")
+	}
+	for i := range w.Agents {
+		if w.Agents[i].ID == old.ID {
+			t.Fatal("dead agent is still in the world")
+		}
+	}
+	conserved(t, w)
+}
+
+func TestPopulationStaysUnderCap(t *testing.T) {
+	w := scenario.Build(small(7, 4))
+	w.Population.ImmigrationRate = 1000
+	w.Population.BirthRate = 1000
+	w.Population.ProsperousAbove = 0
+	for i := range w.Agents {
+		w.Agents[i].Happiness = 1
+	}
+
+	start := len(w.Agents)
+	for range 400 {
+		w.Tick(step)
+		if n, limit := len(w.Agents), w.PopulationCap(); n > limit {
+			t.Fatalf("tick %d: %d agents, cap %d", w.Ticks, n, limit)
+		}
+	}
+	if len(w.Agents) <= start {
+		t.Fatalf("population stayed at %d despite room for %d", len(w.Agents), w.PopulationCap())
+	}
+	conserved(t, w)
+}
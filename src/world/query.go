@@ -1,13 +1,61 @@
 package world
 
-func (w *World) IsPointBlocked(x, z float64) bool {
-	return w.Grid.IsPointBlocked(x, z)
+import (
+	"time"
+
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/events"
+	worldQuery "veatla/simulator/src/world-query"
+
+	"github.com/google/uuid"
+)
+
+func (w *World) IsPointBlocked(x, z float64, who worldQuery.Passer) bool {
+	return w.Grid.IsPointBlocked(x, z) || w.gateBlocks(x, z, who) || w.zoneBlocks(x, z, who)
 }
 
 func (w *World) RandomFloat() float64 {
 	return w.rng.Float64()
 }
 
+func (w *World) NewID() uuid.UUID {
+	id, err := uuid.NewRandomFromReader(w.rng)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func (w *World) Emit(e events.Event) {
+	w.Events.Publish(e)
+}
+
+func (w *World) DayLength() time.Duration {
+	return w.Calendar.DayLength
+}
+
+// Now is the current calendar time.
+func (w *World) Now() calendar.Time {
+	return w.Calendar.At(w.elapsed)
+}
+
+// SetDayLength changes how long an in-world day lasts and rescales the
+// population and economy rules that are measured in days.
+func (w *World) SetDayLength(d time.Duration) {
+	old := w.Calendar.DayLength
+	if d <= 0 || d == old {
+		return
+	}
+	scale := func(t time.Duration) time.Duration {
+		return time.Duration(float64(t) * float64(d) / float64(old))
+	}
+	w.Population.Interval = scale(w.Population.Interval)
+	w.Population.MinLifespan = scale(w.Population.MinLifespan)
+	w.Population.MaxLifespan = scale(w.Population.MaxLifespan)
+	w.Economy.ShiftLength = scale(w.Economy.ShiftLength)
+	w.Calendar.DayLength = d
+}
+
 func (w *World) GetBoundaries() (width, height float64) {
 	return w.Width, w.Height
 }
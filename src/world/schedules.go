@@ -0,0 +1,106 @@
+package world
+
+import (
+	"math"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	spatialhash "veatla/simulator/src/spatial-hash"
+
+	"github.com/google/uuid"
+)
+
+// gatherReach is how close to a door an agent must get to count as at home,
+// work, the market or the tavern, so a crowd can gather around it.
+const gatherReach = 2.5
+
+// scheduleTick sends agents where their routine has them at this hour.
+// Agents busy with a job finish it first, and agents with nowhere to go
+// wander.
+func (w *World) scheduleTick() {
+	hour := w.Now().Hour
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if w.busy(a.ID) {
+			continue
+		}
+		act := a.Schedule.At(hour)
+		changed := act != a.Schedule.Current
+		if !changed && (a.HasGoal() || a.GoalFailed()) {
+			continue
+		}
+		x, z, ok := w.activityPlace(a, act)
+		if !changed && !ok {
+			continue
+		}
+		if changed {
+			a.Schedule.Current = act
+			w.Emit(events.ActivityStarted{AgentID: a.ID, Activity: string(act)})
+		}
+		if !ok {
+			a.ClearGoal()
+			continue
+		}
+		a.SetGoalWithin(w, x, z, gatherReach)
+	}
+}
+
+// onDuty reports whether an agent should be looking for work now. Agents
+// without a routine work whenever it is light.
+func (w *World) onDuty(a *agents.Agent) bool {
+	now := w.Now()
+	if len(a.Schedule.Slots) == 0 {
+		return !now.Night
+	}
+	return a.Schedule.At(now.Hour) == agents.ActivityWork
+}
+
+// busy reports whether an agent has a construction, field or gathering job.
+func (w *World) busy(id uuid.UUID) bool {
+	_, building := w.jobs[id]
+	_, farming := w.fieldJobs[id]
+	_, gathering := w.gatherJobs[id]
+	return building || farming || gathering
+}
+
+// activityPlace returns where an agent goes for an activity: beside its
+// home or workplace, or the nearest market or tavern it knows of. The
+// homeless have nowhere to go home to.
+func (w *World) activityPlace(a *agents.Agent, act agents.Activity) (float64, float64, bool) {
+	switch act {
+	case agents.ActivityHome:
+		if i := w.buildingIndex(a.Home); i >= 0 && a.Home != uuid.Nil {
+			return w.doorPoint(w.Buildings[i].Obstacle)
+		}
+	case agents.ActivityMarket:
+		return w.nearestDoor(a, constructions.BuildingMarket)
+	case agents.ActivityTavern:
+		return w.nearestDoor(a, constructions.BuildingTavern)
+	case agents.ActivityWork:
+		if i := w.buildingIndex(a.Workplace); i >= 0 && a.Workplace != uuid.Nil {
+			return w.doorPoint(w.Buildings[i].Obstacle)
+		}
+	}
+	return 0, 0, false
+}
+
+// nearestDoor returns the door of the building of the given kind closest to
+// the agent, among those it knows of.
+func (w *World) nearestDoor(a *agents.Agent, kind constructions.BuildingKind) (float64, float64, bool) {
+	best, bestDist := -1, math.Inf(1)
+	for i := range w.Buildings {
+		b := &w.Buildings[i]
+		if b.Kind != kind || !a.Memory.Knows(b.ID) {
+			continue
+		}
+		box := spatialhash.AABB{MinX: b.MinX, MinZ: b.MinZ, MaxX: b.MaxX, MaxZ: b.MaxZ}
+		if d := box.DistanceTo(a.X, a.Z); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 0 {
+		return 0, 0, false
+	}
+	return w.doorPoint(w.Buildings[best].Obstacle)
+}
@@ -0,0 +1,67 @@
+package world
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+)
+
+// setHour moves the clock to hour on the first day.
+func setHour(w *World, hour float64) {
+	h := math.Mod(hour-w.Calendar.StartHour+24, 24)
+	w.elapsed = time.Duration(h / 24 * float64(w.Calendar.DayLength))
+}
+
+func TestScheduleSendsAgentsAlong(t *testing.T) {
+	w, a, store, _ := builderWorld(t)
+	house := constructions.CreateBuilding(w.NewID(), constructions.BuildingHouse, 30, 30, 33, 33, 2)
+	w.AddBuilding(house)
+	a.Home, a.Workplace = house.ID, store
+	var started []string
+	w.Events.Subscribe(func(r events.Record) {
+		if e, ok := r.Event.(events.ActivityStarted); ok {
+			started = append(started, e.Activity)
+		}
+	})
+
+	for _, tc := range []struct {
+		hour  float64
+		place constructions.Obstacle
+	}{
+		{22, house.Obstacle},
+		{8, w.Buildings[w.buildingIndex(store)].Obstacle},
+	} {
+		setHour(w, tc.hour)
+		w.scheduleTick()
+		x, z, _ := w.doorPoint(tc.place)
+		if !a.HasGoal() || math.Hypot(a.Wandering.X-x, a.Wandering.Z-z) > 1e-9 {
+			t.Fatalf("at %v: goal (%v, %v), want the door at (%v, %v)", tc.hour, a.Wandering.X, a.Wandering.Z, x, z)
+		}
+		// A second look at the same hour changes nothing.
+		w.scheduleTick()
+	}
+
+	// Agents with a job finish it before following their routine.
+	w.jobs[a.ID] = &job{step: stepWait}
+	setHour(w, 16)
+	w.scheduleTick()
+	if a.Schedule.Current != agents.ActivityWork {
+		t.Errorf("busy agent switched to %s", a.Schedule.Current)
+	}
+	delete(w.jobs, a.ID)
+
+	// Nobody knows of a market, so the agent idles there.
+	w.scheduleTick()
+	if a.HasGoal() {
+		t.Error("agent heads for a market it does not know")
+	}
+	want := []string{string(agents.ActivityHome), string(agents.ActivityWork), string(agents.ActivityMarket)}
+	if !slices.Equal(started, want) {
+		t.Errorf("activities started = %v, want %v", started, want)
+	}
+}
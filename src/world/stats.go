@@ -0,0 +1,39 @@
+package world
+
+import "veatla/simulator/src/agents"
+
+// TickStats summarizes the work done during the last tick. Failures and stuck
+// agents are reported on the event bus instead.
+type TickStats struct {
+	Agents         int
+	AgentsMoved    int
+	PathRequests   int
+	PathExpansions int
+}
+
+// LastTickStats returns the stats of the most recent tick.
+func (w *World) LastTickStats() TickStats {
+	return w.lastTick
+}
+
+// countPaths sets the path stats of the tick from the agents' own counters.
+// It runs after every tick phase, so searches made outside the agent
+// pipeline, such as by schedules, jobs or a closing gate, are counted too.
+// Agents removed during the tick are still counted through departed.
+func (w *World) countPaths() {
+	total := w.departed
+	for i := range w.Agents {
+		total = addStats(total, w.Agents[i].Stats())
+	}
+	w.lastTick.PathRequests = total.PathRequests - w.pathTotal.PathRequests
+	w.lastTick.PathExpansions = total.PathExpansions - w.pathTotal.PathExpansions
+	w.pathTotal = total
+}
+
+func addStats(s, o agents.Stats) agents.Stats {
+	s.PathRequests += o.PathRequests
+	s.PathFailures += o.PathFailures
+	s.PathExpansions += o.PathExpansions
+	s.StuckEvents += o.StuckEvents
+	return s
+}
@@ -1,43 +1,53 @@
 package world
 
 import (
-	"sync"
 	"time"
 
 	"veatla/simulator/src/agents"
 )
 
+// Tick advances the world by one fixed step and returns the agents that moved.
+func (w *World) Tick(dt time.Duration) []agents.Agent {
+	w.Ticks++
+	w.elapsed += dt
+	w.Events.SetTick(w.Ticks)
+	w.weatherTick(dt)
+	w.perceptionTick()
+	updated := w.AgentsTick(dt)
+	w.memoryTick(dt)
+	w.populationTick(dt)
+	w.marketTick()
+	w.economyTick(dt)
+	w.scheduleTick()
+	w.householdTick()
+	w.constructionTick(dt)
+	w.farmingTick(dt)
+	w.gatheringTick(dt)
+	w.countPaths()
+	w.publishHouseholds()
+	return updated
+}
+
+// AgentsTick runs the agent pipeline:
+//
+//  1. decide: agents sense the world and plan their next state in parallel.
+//     The world is read-only during this phase; each agent works on its own
+//     copy and events are buffered per chunk.
+//  2. resolve: proposals are validated in agent order, rejecting moves into
+//     blocked or out-of-bounds positions.
+//  3. commit: accepted proposals replace the agents, the grid is updated and
+//     buffered events are published in agent order.
+//
+// Results only depend on the world state and seed, not on scheduling.
 func (w *World) AgentsTick(dt time.Duration) []agents.Agent {
 	n := len(w.Agents)
+	w.lastTick = TickStats{Agents: n}
 	if n == 0 {
 		return nil
 	}
 
-	results := make([]agents.Agent, n)
-	changedFlags := make([]bool, n)
-	var wg sync.WaitGroup
-
-	wg.Add(n)
-	for i := range n {
-		go func(i int) {
-			defer wg.Done()
-			agent := &w.Agents[i]
-			changed := agent.Tick(dt, w)
-			results[i] = *agent
-			if changed {
-				changedFlags[i] = true
-			}
-		}(i)
-	}
-	wg.Wait()
-
-	var changedAgents []agents.Agent
-	for i := range n {
-		a := results[i]
-		w.Grid.Insert(a.ID, a.X, a.Z, a.Width+a.X, a.Height+a.Z, false)
-		if changedFlags[i] {
-			changedAgents = append(changedAgents, a)
-		}
-	}
-	return changedAgents
+	b := w.tickBuffers(n)
+	w.decide(b, dt)
+	w.resolve(b)
+	return w.commit(b)
 }
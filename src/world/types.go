@@ -2,10 +2,24 @@ package world
 
 import (
 	"math/rand"
+	"time"
 
 	"veatla/simulator/src/agents"
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/castle"
 	"veatla/simulator/src/constructions"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/generator"
+	"veatla/simulator/src/goods"
+	"veatla/simulator/src/households"
+	"veatla/simulator/src/ledger"
+	"veatla/simulator/src/market"
+	"veatla/simulator/src/resources"
 	spatialhash "veatla/simulator/src/spatial-hash"
+	"veatla/simulator/src/weather"
+	"veatla/simulator/src/zones"
+
+	"github.com/google/uuid"
 )
 
 type WorldID string
@@ -17,5 +31,72 @@ type World struct {
 	Height    float64
 	Agents    []agents.Agent
 	Obstacles []constructions.Obstacle
-	Grid      spatialhash.SpatialHash
+	Buildings []constructions.Building
+	// Fortifications are the castle's walls, towers and gates.
+	Fortifications []constructions.Fortification
+	// Sites are buildings under construction.
+	Sites []constructions.Site
+	// Zones are designated areas; ZoneIndex finds them by position.
+	Zones     []zones.Zone
+	ZoneIndex zones.Index
+	// Nodes are trees, rock outcrops and ore veins. Depleted trees stay here,
+	// off the grid, until they grow back.
+	Nodes []resources.Node
+	// Items are goods lying on the ground.
+	Items  []goods.Item
+	Grid   spatialhash.SpatialHash
+	Map    *generator.Map
+	Events *events.Bus
+	// Calendar turns elapsed simulated time into dates and times of day.
+	Calendar calendar.Calendar
+	// Weather is the current weather and the state of the ground.
+	Weather weather.State
+	// Ticks is the number of ticks simulated so far.
+	Ticks int
+	// Population controls births, deaths and migration.
+	Population PopulationRules
+	// Markets are the order books of market buildings, in placement order.
+	Markets []*market.Market
+	// PriceHistory records every market's prices each tick.
+	PriceHistory *market.History
+	// Economy controls wages, rent and minting.
+	Economy EconomyRules
+	// Castle is the lord's household: treasury, tithe stores and tax policy.
+	// Its money is held under ledger.Treasury.
+	Castle *castle.Castle
+	// Ledger records every transfer of money.
+	Ledger *ledger.Ledger
+	// Households group agents living in the same house.
+	Households []households.Household
+	// HouseholdDirectory publishes the households for the API.
+	HouseholdDirectory *households.Directory
+	// Archetypes are the agent definitions used for spawning.
+	Archetypes agents.Archetypes
+	lastTick   TickStats
+	// pathTotal is the agents' path stats as of the last tick, and departed
+	// the stats of the agents removed since the world began.
+	pathTotal  agents.Stats
+	departed   agents.Stats
+	pipeline   pipelineBuffers
+	sincePop   time.Duration
+	sinceShift time.Duration
+	sinceDay   time.Duration
+	// sinceWeather is the time since the weather last had a chance to turn.
+	sinceWeather time.Duration
+	jobs         map[uuid.UUID]*job
+	fieldJobs    map[uuid.UUID]*fieldJob
+	gatherJobs   map[uuid.UUID]*gatherJob
+	// elapsed is the simulated time since the start.
+	elapsed time.Duration
+	// agentSlot, buildingSlot and householdSlot map IDs to their index in
+	// Agents, Buildings and Households, so that balance need not scan them
+	// on every transfer. zoneSlot does the same for Zones, which A* looks
+	// up on every expansion.
+	agentSlot     map[uuid.UUID]int
+	buildingSlot  map[uuid.UUID]int
+	householdSlot map[uuid.UUID]int
+	zoneSlot      map[uuid.UUID]int
+	// owed is the fraction of a coin of tax each account has been charged
+	// but not yet paid, by ledger reason.
+	owed map[taxDebt]float64
 }
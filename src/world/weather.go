@@ -0,0 +1,46 @@
+package world
+
+import (
+	"time"
+
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/generator"
+)
+
+// muddiness is how much each terrain softens when wet: bare earth turns to
+// mud, rocky hills barely change.
+var muddiness = map[generator.Terrain]float64{
+	generator.TerrainGrass:  1,
+	generator.TerrainForest: 0.6,
+	generator.TerrainHill:   0.3,
+	generator.TerrainWater:  1,
+	generator.TerrainFord:   1,
+}
+
+// weatherTick soaks or dries the ground and lets the weather turn once an
+// in-world hour.
+func (w *World) weatherTick(dt time.Duration) {
+	day := w.Calendar.DayLength
+	if day <= 0 {
+		return
+	}
+	season := w.Now().Season
+	w.Weather.Settle(season, float64(dt)/float64(day))
+	w.sinceWeather += dt
+	for w.sinceWeather >= day/24 {
+		w.sinceWeather -= day / 24
+		if w.Weather.Turn(w.rng, season) {
+			w.Emit(events.WeatherChanged{Weather: string(w.Weather.Kind)})
+		}
+	}
+}
+
+// MoveCost is how many times longer it takes to cross (x, z) than dry
+// ground on a clear day.
+func (w *World) MoveCost(x, z float64) float64 {
+	mud := 1.0
+	if w.Map != nil {
+		mud = muddiness[w.Map.TileAt(x, z)]
+	}
+	return w.Weather.MoveCost(mud)
+}
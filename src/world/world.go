@@ -3,18 +3,38 @@ package world
 import (
 	"math/rand"
 
+	"veatla/simulator/src/agents"
+	"veatla/simulator/src/calendar"
+	"veatla/simulator/src/castle"
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/households"
+	"veatla/simulator/src/ledger"
+	"veatla/simulator/src/market"
 	spatialhash "veatla/simulator/src/spatial-hash"
+	"veatla/simulator/src/weather"
+	"veatla/simulator/src/zones"
 )
 
+// eventHistory is the number of events kept in the bus ring buffer.
+const eventHistory = 4096
+
 func NewWorld(seed int64, width, height float64) World {
 	return World{
-		Seed:   seed,
-		Width:  width,
-		Height: height,
-		Grid: spatialhash.SpatialHash{
-			CellSize: 1,
-			Cells:    make(map[int64]*spatialhash.Cell),
-		},
-		rng: rand.New(rand.NewSource(seed)),
+		Seed:               seed,
+		Width:              width,
+		Height:             height,
+		Grid:               spatialhash.NewBounded(1, width, height),
+		ZoneIndex:          zones.NewIndex(width, height),
+		Events:             events.NewBus(eventHistory),
+		Calendar:           calendar.Default(),
+		Weather:            weather.State{Kind: weather.Clear},
+		Population:         DefaultPopulationRules(),
+		Archetypes:         agents.DefaultArchetypes(),
+		PriceHistory:       market.NewHistory(priceHistory),
+		Economy:            DefaultEconomyRules(),
+		Ledger:             ledger.New(ledgerHistory),
+		Castle:             castle.New("lord", fiscalHistory),
+		HouseholdDirectory: households.NewDirectory(),
+		rng:                rand.New(rand.NewSource(seed)),
 	}
 }
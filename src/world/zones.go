@@ -0,0 +1,144 @@
+package world
+
+import (
+	"errors"
+
+	"veatla/simulator/src/events"
+	"veatla/simulator/src/farming"
+	spatialhash "veatla/simulator/src/spatial-hash"
+	worldQuery "veatla/simulator/src/world-query"
+	"veatla/simulator/src/zones"
+
+	"github.com/google/uuid"
+)
+
+var ErrZoneNotFound = errors.New("zone not found")
+
+// DesignateZone adds a zone and returns its ID, which callers draw from
+// NewID. Farm fields are sown with
+// farming.DefaultCrop unless they name one, and get their soil from the
+// map. Agents that a new restricted zone keeps out are moved to its edge and
+// replan paths through it.
+func (w *World) DesignateZone(z zones.Zone) (uuid.UUID, error) {
+	if z.Kind == zones.KindFarm {
+		if z.Crop == "" {
+			z.Crop = farming.DefaultCrop
+		}
+		if _, ok := farming.Crops[z.Crop]; !ok {
+			return uuid.Nil, ErrUnknownCrop
+		}
+		z.Soil = w.soilFertility(&z)
+		z.Fertility = z.Soil
+	}
+	w.Zones = append(w.Zones, z)
+	reindex(&w.zoneSlot, w.Zones, len(w.Zones)-1, zoneID)
+	zi := &w.Zones[len(w.Zones)-1]
+	w.ZoneIndex.Insert(zi)
+	w.Emit(events.ZoneDesignated{ZoneID: z.ID, ZoneKind: string(z.Kind), MinX: z.MinX, MinZ: z.MinZ, MaxX: z.MaxX, MaxZ: z.MaxZ})
+	if z.Kind != zones.KindRestricted {
+		return z.ID, nil
+	}
+	box := zoneBox(zi)
+	for i := range w.Agents {
+		a := &w.Agents[i]
+		if !zi.Blocks(a.Faction) {
+			continue
+		}
+		if zi.Contains(a.X, a.Z) {
+			w.eject(a, box)
+		}
+		if pathEnters(a.X, a.Z, a.PathAhead(), zi.Contains) {
+			a.Replan(w)
+		}
+	}
+	return z.ID, nil
+}
+
+// RemoveZone removes a zone. Goods left in a storage zone or a harvested
+// field stay on the ground.
+func (w *World) RemoveZone(id uuid.UUID) error {
+	i := w.zoneIndex(id)
+	if i < 0 {
+		return ErrZoneNotFound
+	}
+	z := w.Zones[i]
+	x, zz := z.Anchor()
+	w.dropStock(z.Stock, x, zz)
+	w.releaseFarmers(id)
+	w.ZoneIndex.Remove(id)
+	w.Zones = append(w.Zones[:i], w.Zones[i+1:]...)
+	delete(w.zoneSlot, id)
+	reindex(&w.zoneSlot, w.Zones, i, zoneID)
+	w.Emit(events.ZoneRemoved{ZoneID: id})
+	return nil
+}
+
+// ZonesAt returns the zones containing (x, z).
+func (w *World) ZonesAt(x, z float64) []*zones.Zone {
+	var found []*zones.Zone
+	for _, id := range w.ZoneIndex.At(nil, x, z) {
+		if i := w.zoneIndex(id); i >= 0 && w.Zones[i].Contains(x, z) {
+			found = append(found, &w.Zones[i])
+		}
+	}
+	return found
+}
+
+// TillField, SowField and HarvestField move a farm field to its next stage.
+func (w *World) TillField(id uuid.UUID) error    { return w.workField(id, (*zones.Zone).Till) }
+func (w *World) SowField(id uuid.UUID) error     { return w.workField(id, (*zones.Zone).Sow) }
+func (w *World) HarvestField(id uuid.UUID) error { return w.workField(id, (*zones.Zone).Harvest) }
+
+func (w *World) workField(id uuid.UUID, work func(*zones.Zone) error) error {
+	i := w.zoneIndex(id)
+	if i < 0 {
+		return ErrZoneNotFound
+	}
+	z := &w.Zones[i]
+	if err := work(z); err != nil {
+		return err
+	}
+	w.Emit(events.FieldWorked{ZoneID: id, Stage: string(z.Stage)})
+	return nil
+}
+
+// zoneBlocks reports whether a restricted zone keeps who out of (x, z). It
+// runs on every A* expansion, so it only looks at the zones the index has
+// at the point, collected without allocating, and finds them by slot.
+func (w *World) zoneBlocks(x, z float64, who worldQuery.Passer) bool {
+	var buf [4]uuid.UUID
+	for _, id := range w.ZoneIndex.At(buf[:0], x, z) {
+		i := w.zoneIndex(id)
+		if i < 0 {
+			continue
+		}
+		zi := &w.Zones[i]
+		if zi.Kind == zones.KindRestricted && zi.Contains(x, z) && zi.Blocks(who.Faction) {
+			return true
+		}
+	}
+	return false
+}
+
+// storeApproach returns where an agent stands to use a building's or storage
+// zone's stock.
+func (w *World) storeApproach(id uuid.UUID) (float64, float64, bool) {
+	if i := w.buildingIndex(id); i >= 0 {
+		return w.doorPoint(w.Buildings[i].Obstacle)
+	}
+	if i := w.zoneIndex(id); i >= 0 {
+		x, z := w.Zones[i].Anchor()
+		return x, z, true
+	}
+	return 0, 0, false
+}
+
+func zoneBox(z *zones.Zone) spatialhash.AABB {
+	return spatialhash.AABB{MinX: z.MinX, MinZ: z.MinZ, MaxX: z.MaxX, MaxZ: z.MaxZ}
+}
+
+func (w *World) zoneIndex(id uuid.UUID) int {
+	return slotOf(w.zoneSlot, id)
+}
+
+func zoneID(z *zones.Zone) uuid.UUID { return z.ID }
@@ -0,0 +1,43 @@
+package zones
+
+import (
+	spatialhash "veatla/simulator/src/spatial-hash"
+
+	"github.com/google/uuid"
+)
+
+// Index finds zones by position. It keeps zone bounds in a spatial hash of
+// its own so that zones, which may overlap obstacles and each other, never
+// show up in movement queries.
+type Index struct {
+	grid spatialhash.SpatialHash
+}
+
+// zoneCell is the cell size of the index; zones are usually several tiles.
+const zoneCell = 4
+
+// NewIndex creates an index covering [0, width] x [0, height].
+func NewIndex(width, height float64) Index {
+	return Index{grid: spatialhash.NewBounded(zoneCell, width, height)}
+}
+
+// Insert adds a zone's bounds.
+func (ix *Index) Insert(z *Zone) {
+	ix.grid.Insert(z.ID, z.MinX, z.MinZ, z.MaxX, z.MaxZ, true)
+}
+
+// Remove drops a zone.
+func (ix *Index) Remove(id uuid.UUID) {
+	ix.grid.Remove(id)
+}
+
+// At appends to dst the zones whose bounds contain (x, z). Callers check
+// Contains for polygon zones.
+func (ix *Index) At(dst []uuid.UUID, x, z float64) []uuid.UUID {
+	return ix.grid.QueryAABB(dst, spatialhash.AABB{MinX: x, MinZ: z, MaxX: x, MaxZ: z}, spatialhash.KindObstacle)
+}
+
+// Overlapping appends to dst the zones whose bounds overlap box.
+func (ix *Index) Overlapping(dst []uuid.UUID, box spatialhash.AABB) []uuid.UUID {
+	return ix.grid.QueryAABB(dst, box, spatialhash.KindObstacle)
+}
@@ -0,0 +1,209 @@
+package zones
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	"veatla/simulator/src/goods"
+
+	"github.com/google/uuid"
+)
+
+// Kind is what a zone is designated for.
+type Kind string
+
+const (
+	KindFarm        Kind = "farm"
+	KindStorage     Kind = "storage"
+	KindResidential Kind = "residential"
+	KindRestricted  Kind = "restricted"
+)
+
+// Stage is where a farm field is in its yearly cycle.
+type Stage string
+
+const (
+	StageFallow Stage = "fallow"
+	StageTilled Stage = "tilled"
+	StageSown   Stage = "sown"
+	StageRipe   Stage = "ripe"
+	// StageRotten is a failed or unharvested crop; it must be tilled under.
+	StageRotten Stage = "rotten"
+)
+
+var (
+	ErrUnknownKind = errors.New("unknown zone kind")
+	ErrBadPolygon  = errors.New("zone needs at least three points and an area")
+	ErrNotAField   = errors.New("zone is not a farm field")
+	ErrWrongStage  = errors.New("field is not ready for that")
+)
+
+// Point is a polygon vertex in world coordinates.
+type Point struct {
+	X float64 `json:"x"`
+	Z float64 `json:"z"`
+}
+
+// Zone is a designated area of the map. Unlike obstacles, zones do not block
+// movement, except restricted zones for agents outside their Factions.
+type Zone struct {
+	ID     uuid.UUID
+	Kind   Kind
+	Points []Point
+	// MinX..MaxZ are the bounds of Points.
+	MinX, MinZ float64
+	MaxX, MaxZ float64
+	// Stage is the field stage; farm zones only.
+	Stage Stage
+	// Crop is what the field is sown with.
+	Crop string
+	// Soil is the field's natural fertility and Fertility what is left of it
+	// after harvests, both in [0, 1].
+	Soil, Fertility float64
+	// Growth is how far the crop is to ripe, in [0, 1], and Health what
+	// share of it survives; RipeDays is how long it has stood ripe.
+	Growth, Health, RipeDays float64
+	// Work is the progress of the current tilling, sowing or harvest, in
+	// [0, 1].
+	Work float64
+	// Stock holds goods left in a storage zone, or the harvest of a farm
+	// field waiting to be carried off.
+	Stock goods.Stock
+	// Factions may enter a restricted zone.
+	Factions []string
+}
+
+// NewRect creates a rectangular zone.
+func NewRect(id uuid.UUID, kind Kind, minX, minZ, maxX, maxZ float64) (Zone, error) {
+	return NewPolygon(id, kind, []Point{{minX, minZ}, {maxX, minZ}, {maxX, maxZ}, {minX, maxZ}})
+}
+
+// NewPolygon creates a zone with the given ID bounded by a simple polygon.
+func NewPolygon(id uuid.UUID, kind Kind, points []Point) (Zone, error) {
+	switch kind {
+	case KindFarm, KindStorage, KindResidential, KindRestricted:
+	default:
+		return Zone{}, ErrUnknownKind
+	}
+	if len(points) < 3 || area(points) == 0 {
+		return Zone{}, ErrBadPolygon
+	}
+	z := Zone{
+		ID:     id,
+		Kind:   kind,
+		Points: slices.Clone(points),
+		MinX:   math.Inf(1),
+		MinZ:   math.Inf(1),
+		MaxX:   math.Inf(-1),
+		MaxZ:   math.Inf(-1),
+	}
+	for _, p := range points {
+		z.MinX, z.MaxX = math.Min(z.MinX, p.X), math.Max(z.MaxX, p.X)
+		z.MinZ, z.MaxZ = math.Min(z.MinZ, p.Z), math.Max(z.MaxZ, p.Z)
+	}
+	switch kind {
+	case KindFarm:
+		z.Stage = StageFallow
+		z.Stock = goods.Stock{}
+	case KindStorage:
+		z.Stock = goods.Stock{}
+	}
+	return z, nil
+}
+
+// Contains reports whether (x, z) lies inside the zone.
+func (z *Zone) Contains(x, zz float64) bool {
+	if x < z.MinX || x > z.MaxX || zz < z.MinZ || zz > z.MaxZ {
+		return false
+	}
+	in := false
+	for i, j := 0, len(z.Points)-1; i < len(z.Points); j, i = i, i+1 {
+		a, b := z.Points[i], z.Points[j]
+		if (a.Z > zz) != (b.Z > zz) && x < (b.X-a.X)*(zz-a.Z)/(b.Z-a.Z)+a.X {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Area is the zone's area in square tiles.
+func (z *Zone) Area() float64 { return area(z.Points) }
+
+// Anchor returns a point inside the zone agents can walk to.
+func (z *Zone) Anchor() (float64, float64) {
+	cx, cz := (z.MinX+z.MaxX)/2, (z.MinZ+z.MaxZ)/2
+	if z.Contains(cx, cz) {
+		return cx, cz
+	}
+	// concave polygon: the midpoint of the first diagonal that is inside
+	for i := 2; i < len(z.Points); i++ {
+		x, zz := (z.Points[0].X+z.Points[i].X)/2, (z.Points[0].Z+z.Points[i].Z)/2
+		if z.Contains(x, zz) {
+			return x, zz
+		}
+	}
+	return z.Points[0].X, z.Points[0].Z
+}
+
+// Admits reports whether faction may enter a restricted zone.
+func (z *Zone) Admits(faction string) bool {
+	return faction != "" && slices.Contains(z.Factions, faction)
+}
+
+// Blocks reports whether the zone keeps a member of faction out.
+func (z *Zone) Blocks(faction string) bool {
+	return z.Kind == KindRestricted && !z.Admits(faction)
+}
+
+// Till readies a fallow field for sowing, or turns a rotten crop under.
+func (z *Zone) Till() error {
+	if z.Stage == StageRotten {
+		return z.advance(StageRotten, StageTilled)
+	}
+	return z.advance(StageFallow, StageTilled)
+}
+
+// Sow plants a tilled field.
+func (z *Zone) Sow() error {
+	if err := z.advance(StageTilled, StageSown); err != nil {
+		return err
+	}
+	z.Growth, z.Health, z.RipeDays = 0, 1, 0
+	return nil
+}
+
+// Ripen marks a sown field as ready to harvest.
+func (z *Zone) Ripen() error { return z.advance(StageSown, StageRipe) }
+
+// Harvest returns a ripe field to fallow.
+func (z *Zone) Harvest() error { return z.advance(StageRipe, StageFallow) }
+
+// Rot spoils a sown or ripe crop.
+func (z *Zone) Rot() error {
+	if z.Stage == StageSown {
+		return z.advance(StageSown, StageRotten)
+	}
+	return z.advance(StageRipe, StageRotten)
+}
+
+func (z *Zone) advance(from, to Stage) error {
+	if z.Kind != KindFarm {
+		return ErrNotAField
+	}
+	if z.Stage != from {
+		return ErrWrongStage
+	}
+	z.Stage = to
+	z.Work = 0
+	return nil
+}
+
+// area is the shoelace area of a polygon.
+func area(points []Point) float64 {
+	var s float64
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		s += (points[j].X + points[i].X) * (points[j].Z - points[i].Z)
+	}
+	return math.Abs(s) / 2
+}
@@ -0,0 +1,96 @@
+package zones
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func field(t *testing.T, stage Stage) Zone {
+	t.Helper()
+	z, err := NewRect(uuid.UUID{15: 1}, KindFarm, 0, 0, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z.Stage = stage
+	return z
+}
+
+func TestStageTransitions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		from Stage
+		do   func(*Zone) error
+		to   Stage
+		err  error
+	}{
+		{"till fallow", StageFallow, (*Zone).Till, StageTilled, nil},
+		{"till rotten", StageRotten, (*Zone).Till, StageTilled, nil},
+		{"till sown", StageSown, (*Zone).Till, StageSown, ErrWrongStage},
+		{"sow tilled", StageTilled, (*Zone).Sow, StageSown, nil},
+		{"sow fallow", StageFallow, (*Zone).Sow, StageFallow, ErrWrongStage},
+		{"ripen sown", StageSown, (*Zone).Ripen, StageRipe, nil},
+		{"ripen tilled", StageTilled, (*Zone).Ripen, StageTilled, ErrWrongStage},
+		{"harvest ripe", StageRipe, (*Zone).Harvest, StageFallow, nil},
+		{"harvest sown", StageSown, (*Zone).Harvest, StageSown, ErrWrongStage},
+		{"rot sown", StageSown, (*Zone).Rot, StageRotten, nil},
+		{"rot ripe", StageRipe, (*Zone).Rot, StageRotten, nil},
+		{"rot fallow", StageFallow, (*Zone).Rot, StageFallow, ErrWrongStage},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			z := field(t, tc.from)
+			z.Work = 0.5
+			err := tc.do(&z)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if z.Stage != tc.to {
+				t.Errorf("stage = %s, want %s", z.Stage, tc.to)
+			}
+			// A new stage starts its work afresh; a refused one keeps it.
+			want := 0.5
+			if err == nil {
+				want = 0
+			}
+			if z.Work != want {
+				t.Errorf("work = %v, want %v", z.Work, want)
+			}
+		})
+	}
+}
+
+func TestSowResetsCrop(t *testing.T) {
+	z := field(t, StageTilled)
+	z.Growth, z.Health, z.RipeDays = 0.7, 0.2, 3
+	if err := z.Sow(); err != nil {
+		t.Fatal(err)
+	}
+	if z.Growth != 0 || z.Health != 1 || z.RipeDays != 0 {
+		t.Fatalf("new crop = %v/%v/%v, want 0/1/0", z.Growth, z.Health, z.RipeDays)
+	}
+}
+
+func TestStagesOnlyOnFields(t *testing.T) {
+	z, err := NewRect(uuid.UUID{15: 1}, KindStorage, 0, 0, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, do := range []func(*Zone) error{(*Zone).Till, (*Zone).Sow, (*Zone).Ripen, (*Zone).Harvest, (*Zone).Rot} {
+		if err := do(&z); !errors.Is(err, ErrNotAField) {
+			t.Errorf("err = %v, want %v", err, ErrNotAField)
+		}
+	}
+}
+
+func TestFullCycle(t *testing.T) {
+	z := field(t, StageFallow)
+	for _, do := range []func(*Zone) error{(*Zone).Till, (*Zone).Sow, (*Zone).Ripen, (*Zone).Rot, (*Zone).Till, (*Zone).Sow, (*Zone).Ripen, (*Zone).Harvest} {
+		if err := do(&z); err != nil {
+			t.Fatalf("at %s: %v", z.Stage, err)
+		}
+	}
+	if z.Stage != StageFallow {
+		t.Fatalf("stage = %s, want %s", z.Stage, StageFallow)
+	}
+}
@@ -8,6 +8,30 @@ type AgentUpdate = {
   rotation: number;
   type: string;
   path?: Array<{ x: number; z: number }>;
+  carrying?: Record<string, number>;
+  load?: number;
+};
+
+type ItemUpdate = {
+  id: string;
+  x: number;
+  z: number;
+  good: string;
+  qty: number;
+};
+
+type ZoneUpdate = {
+  id: string;
+  type: string;
+  points: Array<{ x: number; z: number }>;
+  stage?: string;
+};
+
+const zoneColors: Record<string, number> = {
+  farm: 0x6b8e23,
+  storage: 0xc2a060,
+  residential: 0x4682b4,
+  restricted: 0xb22222,
 };
 
 type ObstacleUpdate = {
@@ -17,13 +41,45 @@ type ObstacleUpdate = {
   maxX: number;
   maxZ: number;
   type: string;
+  closed?: boolean;
 };
 
+type GameTime = {
+  days: number;
+  year: number;
+  season: string;
+  day: number;
+  hour: number;
+  light: number;
+  night: boolean;
+};
+
+type Weather = {
+  kind: string;
+  wetness: number;
+  cover: number;
+};
+
+const weatherTints: Record<string, number> = {
+  rain: 0x3050a0,
+  storm: 0x202840,
+  snow: 0xe0e8ff,
+};
+
+function formatTime(t: GameTime) {
+  const h = Math.floor(t.hour);
+  const m = Math.floor((t.hour - h) * 60);
+  const pad = (n: number) => String(n).padStart(2, "0");
+  return `${t.season} day ${t.day + 1}, year ${t.year + 1} ${pad(h)}:${pad(m)}`;
+}
+
 function getRotationFromVelocity(vx: number, vz: number) {
   return Math.atan2(vz, vx);
 }
 function App() {
   const [tick, setTick] = useState<number | null>(null);
+  const [time, setTime] = useState<GameTime | null>(null);
+  const [weather, setWeather] = useState<Weather | null>(null);
   const stageRef = useRef<HTMLDivElement | null>(null);
   const appRef = useRef<PIXI.Application | null>(null);
   const container = useRef<PIXI.Container>(new PIXI.Container());
@@ -31,6 +87,10 @@ function App() {
   const obstaclesRef = useRef<Map<string, PIXI.Graphics>>(new Map());
   const linesRef = useRef<Map<string, PIXI.Graphics>>(new Map());
   const targetsRef = useRef<Map<string, PIXI.Graphics>>(new Map());
+  const itemsRef = useRef<Map<string, PIXI.Graphics>>(new Map());
+  const zonesRef = useRef<Map<string, PIXI.Graphics>>(new Map());
+  const nightRef = useRef<PIXI.Graphics | null>(null);
+  const weatherRef = useRef<PIXI.Graphics | null>(null);
   useEffect(() => {
     if (!stageRef.current) return;
     const app = new PIXI.Application();
@@ -63,17 +123,46 @@ function App() {
       try {
         const data = JSON.parse(ev.data) as {
           tick: number;
+          time?: GameTime;
+          weather?: Weather;
           updated: AgentUpdate[];
           obstacles: ObstacleUpdate[];
+          removed?: string[];
+          items?: ItemUpdate[];
+          zones?: ZoneUpdate[];
         };
         setTick(data.tick);
+        if (data.time) setTime(data.time);
+        if (data.weather) setWeather(data.weather);
         const app = appRef.current;
         if (!app) return;
         const W = app.renderer.width;
         const H = app.renderer.height;
 
+        // darken the map as the light fades
+        if (!nightRef.current) {
+          nightRef.current = new PIXI.Graphics();
+          nightRef.current.zIndex = 20;
+          container.current.addChild(nightRef.current);
+        }
+        nightRef.current.clear();
+        nightRef.current.rect(0, 0, W, H);
+        nightRef.current.fill({ color: 0x0a1030, alpha: (1 - (data.time?.light ?? 1)) * 0.6 });
+
+        // wash the map in the colour of the weather
+        if (!weatherRef.current) {
+          weatherRef.current = new PIXI.Graphics();
+          weatherRef.current.zIndex = 19;
+          container.current.addChild(weatherRef.current);
+        }
+        weatherRef.current.clear();
+        const tint = weatherTints[data.weather?.kind ?? "clear"];
+        if (tint !== undefined) {
+          weatherRef.current.rect(0, 0, W, H);
+          weatherRef.current.fill({ color: tint, alpha: 0.2 });
+        }
+
         data.updated.forEach((u) => {
-          if (u.type !== "agent") return;
           let g = spritesRef.current.get(u.id);
           const screenX = (u.x / 50) * W;
           const screenY = (u.z / 50) * H;
@@ -110,6 +199,8 @@ function App() {
             g.x += (screenX - g.x) * 0.6;
             g.y += (screenY - g.y) * 0.6;
           }
+          // loaded agents (haulers) are tinted orange
+          g.tint = u.carrying ? 0xff8800 : 0xffffff;
 
           // draw debug path waypoints
           let line = linesRef.current.get(u.id);
@@ -172,7 +263,80 @@ function App() {
           }
         });
 
+        data.removed?.forEach((id) => {
+          for (const refs of [spritesRef, linesRef, targetsRef]) {
+            const g = refs.current.get(id);
+            if (g) {
+              container.current.removeChild(g);
+              g.destroy();
+              refs.current.delete(id);
+            }
+          }
+        });
+
+        const seenItems = new Set<string>();
+        data.items?.forEach((it) => {
+          seenItems.add(it.id);
+          if (itemsRef.current.has(it.id)) return;
+          const g = new PIXI.Graphics();
+          g.rect(-3, -3, 6, 6);
+          g.fill(0xaa7744);
+          g.x = (it.x / 50) * W;
+          g.y = (it.z / 50) * H;
+          g.zIndex = 3;
+          container.current.addChild(g);
+          itemsRef.current.set(it.id, g);
+        });
+        itemsRef.current.forEach((g, id) => {
+          if (seenItems.has(id)) return;
+          container.current.removeChild(g);
+          g.destroy();
+          itemsRef.current.delete(id);
+        });
+
+        const seenZones = new Set<string>();
+        data.zones?.forEach((z) => {
+          seenZones.add(z.id);
+          let g = zonesRef.current.get(z.id);
+          if (!g) {
+            g = new PIXI.Graphics();
+            g.zIndex = 0;
+            container.current.addChild(g);
+            zonesRef.current.set(z.id, g);
+          }
+          // zones are redrawn each frame since fields change stage
+          g.clear();
+          g.poly(z.points.flatMap((p) => [(p.x / 50) * W, (p.z / 50) * H]));
+          const alpha = z.stage === "ripe" ? 0.5 : z.stage === "sown" ? 0.35 : 0.2;
+          g.fill({ color: zoneColors[z.type] ?? 0x888888, alpha });
+        });
+        zonesRef.current.forEach((g, id) => {
+          if (seenZones.has(id)) return;
+          container.current.removeChild(g);
+          g.destroy();
+          zonesRef.current.delete(id);
+        });
+
         data.obstacles.forEach((o) => {
+          if (o.type === "gate") {
+            // gates are redrawn each frame since they open and close
+            let g = obstaclesRef.current.get(o.id);
+            if (!g) {
+              g = new PIXI.Graphics();
+              g.zIndex = 1;
+              container.current.addChild(g);
+              obstaclesRef.current.set(o.id, g);
+            }
+            g.clear();
+            g.rect(
+              (o.minX / 50) * W,
+              (o.minZ / 50) * H,
+              ((o.maxX - o.minX) / 50) * W,
+              ((o.maxZ - o.minZ) / 50) * H,
+            );
+            g.fill({ color: 0x8b5a2b, alpha: o.closed ? 1 : 0.25 });
+            return;
+          }
           if (o.type !== "obstacle") return;
           let g = obstaclesRef.current.get(o.id);
           if (!g) {
@@ -207,6 +371,12 @@ function App() {
         <div style={{ marginBottom: 8 }}>
           <strong>Tick:</strong> {tick ?? "-"}
         </div>
+        <div style={{ marginBottom: 8 }}>
+          <strong>Time:</strong> {time ? formatTime(time) : "-"}
+        </div>
+        <div style={{ marginBottom: 8 }}>
+          <strong>Weather:</strong> {weather?.kind ?? "-"}
+        </div>
         <div>
           <em>Agents rendered with Pixi.js</em>
         </div>